@@ -1,12 +1,10 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -27,9 +25,21 @@ DSL Marker Format:
   ::GEMINI:ID:: ... content ... ::  # Task for Gemini
   ::DONE:ID,ID:: ... ::              # Mark completed tasks
 
+Agent Tags:
+  LARS and GEMINI are the defaults. Configure your own team in project.yaml
+  (used with --project) or in ~/.dppm/config.yaml:
+
+    collab:
+      agents: [CLAUDE, CODEX, GEMINI, REVIEW]
+
+  Tags are upper-case letters, digits and underscores; DONE is reserved.
+
 Usage:
   dppm collab find [path...]          # Find all DSL tasks
+  dppm collab next --agent LARS       # What an agent can start now
   dppm collab clean [path...]         # Remove completed tasks
+  dppm collab restore                 # Undo the last clean
+  dppm collab sync --project X --phase P1  # Create tasks from markers
   dppm collab wiki                    # Show collaboration guides
 
 Examples:
@@ -47,17 +57,47 @@ Searches for AI collaboration markers in markdown files.
 Shows active tasks assigned to different AI models.
 
 Markers searched for:
-  • ::LARS:ID::    - Tasks for LARS/Claude
-  • ::GEMINI:ID::  - Tasks for Gemini
+  • ::AGENT:ID::   - Tasks for one of the configured agents
+                     (default LARS and GEMINI, see 'dppm collab --help')
   • ::DONE:ID::    - Completed task markers
 
-The search excludes ai-dsl.sh and ai-dsl.md files automatically.`,
+Markers with a tag that is not a configured agent are flagged, so typos
+like ::GEMNI:3:: do not go unnoticed.
+
+Each block is shown with its line span, first line of text and the IDs on
+its "Prerequisites:" line. --output json prints the parsed blocks.
+
+With --agent only that agent's queue is shown: its markers whose IDs are
+not yet listed in a DONE marker of the same file. 'dppm collab next' also
+takes prerequisites into account.
+
+The search excludes ai-dsl.sh and ai-dsl.md files automatically.
+
+Examples:
+  dppm collab find docs/
+  dppm collab find --agent CODEX
+  dppm collab find docs/ --project web-app --agent REVIEW`,
 	Run: func(cmd *cobra.Command, args []string) {
 		searchPaths := []string{"."}
 		if len(args) > 0 {
 			searchPaths = args
 		}
-		findCollabTasks(searchPaths)
+		projectID, _ := cmd.Flags().GetString("project")
+		agent, _ := cmd.Flags().GetString("agent")
+
+		agents, err := collabAgents(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if agent != "" {
+			agent = strings.ToUpper(agent)
+			if !containsString(agents, agent) {
+				fmt.Fprintf(os.Stderr, "Error: Unknown agent '%s'. Configured agents: %s\n", agent, strings.Join(agents, ", "))
+				os.Exit(1)
+			}
+		}
+		findCollabTasks(searchPaths, agents, agent)
 	},
 }
 
@@ -72,16 +112,39 @@ This helps maintain clean documentation by removing completed collaboration task
 Process:
   1. Finds all ::DONE:ID,ID:: markers
   2. Extracts comma-separated task IDs
-  3. Removes corresponding ::LARS:ID:: and ::GEMINI:ID:: blocks
+  3. Removes the corresponding blocks of all configured agents, including
+     multi-line blocks, together with the blank line they leave behind
   4. Removes the DONE markers themselves
 
-Safety: Creates backup files (.bak) before making changes.`,
+Files with markers that don't parse (a block never closed with ::) are
+skipped and the offending lines are reported.
+
+Safety: Every changed file is first copied to a timestamped backup folder
+in ~/.dppm/collab-backups; 'dppm collab restore' rolls the last clean back.
+--dry-run prints a unified diff of what would be removed and writes nothing.
+
+Examples:
+  dppm collab clean --dry-run docs/   # Preview the removals as a diff
+  dppm collab clean docs/             # Remove completed tasks
+  dppm collab restore                 # Undo the last clean`,
 	Run: func(cmd *cobra.Command, args []string) {
 		searchPaths := []string{"."}
 		if len(args) > 0 {
 			searchPaths = args
 		}
-		cleanCompletedTasks(searchPaths)
+		projectID, _ := cmd.Flags().GetString("project")
+
+		agents, err := collabAgents(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if err := cleanCompletedTasks(searchPaths, agents, dryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -110,20 +173,18 @@ Available Topics:
 	},
 }
 
-func findCollabTasks(searchPaths []string) {
-	fmt.Println("🔍 Searching for AI collaboration tasks...")
-	fmt.Println("==========================================")
-	fmt.Println("Path(s):", strings.Join(searchPaths, ", "))
-	fmt.Println()
-
-	dslRegex := regexp.MustCompile(`::(LARS|GEMINI|DONE):[^:]*::`)
-	foundAny := false
-
+// parseCollabFiles parses the markers of every markdown file below the search
+// paths. ai-dsl files (which hold marker examples) are skipped.
+func parseCollabFiles(searchPaths []string) []CollabDoc {
+	var docs []CollabDoc
 	for _, searchPath := range searchPaths {
 		err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return nil // Skip files with errors
 			}
+			if info.IsDir() {
+				return nil
+			}
 
 			// Skip non-markdown files
 			if !strings.HasSuffix(strings.ToLower(path), ".md") {
@@ -135,31 +196,11 @@ func findCollabTasks(searchPaths []string) {
 				return nil
 			}
 
-			// Read file content
 			content, err := ioutil.ReadFile(path)
 			if err != nil {
 				return nil // Skip files we can't read
 			}
-
-			// Find all matches
-			matches := dslRegex.FindAllString(string(content), -1)
-			if len(matches) > 0 {
-				foundAny = true
-				fmt.Printf("📄 %s\n", path)
-
-				// Show matches with line numbers
-				scanner := bufio.NewScanner(strings.NewReader(string(content)))
-				lineNum := 1
-				for scanner.Scan() {
-					line := scanner.Text()
-					if dslRegex.MatchString(line) {
-						fmt.Printf("   %d: %s\n", lineNum, strings.TrimSpace(line))
-					}
-					lineNum++
-				}
-				fmt.Println()
-			}
-
+			docs = append(docs, parseCollabMarkers(path, string(content)))
 			return nil
 		})
 
@@ -167,125 +208,213 @@ func findCollabTasks(searchPaths []string) {
 			fmt.Printf("⚠️  Error walking path %s: %v\n", searchPath, err)
 		}
 	}
+	return docs
+}
 
-	if !foundAny {
-		fmt.Println("ℹ️  No DSL collaboration tasks found.")
-		fmt.Println()
-		fmt.Println("💡 To create collaboration tasks, use:")
-		fmt.Println("   ::LARS:1:: Task description for LARS ::")
-		fmt.Println("   ::GEMINI:2:: Task description for Gemini ::")
-		fmt.Println("   ::DONE:1,2:: Mark tasks 1 and 2 as completed ::")
+// lineSpan formats the lines a block covers, e.g. "12" or "12-18"
+func lineSpan(block CollabBlock) string {
+	if block.StartLine == block.EndLine {
+		return strconv.Itoa(block.StartLine)
 	}
+	return fmt.Sprintf("%d-%d", block.StartLine, block.EndLine)
+}
 
-	fmt.Println("==========================================")
-	fmt.Println("✅ Search complete.")
+// describeBlock shows a block as its marker followed by a one-line summary
+func describeBlock(block CollabBlock) string {
+	marker := fmt.Sprintf("::%s:%s::", block.Agent, block.ID)
+	summary := truncate(block.Summary(), 60)
+	if block.IsDone() {
+		marker = fmt.Sprintf("::%s:%s::", doneMarker, strings.Join(block.DoneIDs, ","))
+		summary = block.Body
+	}
+	if summary == "" {
+		return marker
+	}
+	return marker + " " + summary
 }
 
-func cleanCompletedTasks(searchPaths []string) {
-	fmt.Println("🧹 Cleaning completed collaboration tasks...")
+func findCollabTasks(searchPaths []string, agents []string, agent string) {
+	docs := parseCollabFiles(searchPaths)
+	if printStructured(docs) {
+		return
+	}
+
+	fmt.Println("🔍 Searching for AI collaboration tasks...")
 	fmt.Println("==========================================")
 	fmt.Println("Path(s):", strings.Join(searchPaths, ", "))
+	fmt.Println("Agents:", strings.Join(agents, ", "))
+	if agent != "" {
+		fmt.Println("Queue of:", agent)
+	}
 	fmt.Println()
 
-	processedFiles := 0
+	foundAny := false
+	for _, doc := range docs {
+		// IDs already marked DONE in this file drop out of an agent's queue
+		done := doc.doneIDs()
 
-	for _, searchPath := range searchPaths {
-		err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
+		var lines []string
+		for _, problem := range doc.Problems {
+			lines = append(lines, fmt.Sprintf("   %d: ❌ %s", problem.Line, problem.Message))
+		}
+		for _, block := range doc.Blocks {
+			switch {
+			case agent != "":
+				if block.Agent != agent || done[block.ID] {
+					continue
+				}
+			case block.IsDone():
+				lines = append(lines, fmt.Sprintf("   %s: %s", lineSpan(block), describeBlock(block)))
+				continue
+			case !containsString(agents, block.Agent):
+				lines = append(lines, fmt.Sprintf("   %s: ⚠️  unknown agent '%s': %s", lineSpan(block), block.Agent, describeBlock(block)))
+				continue
 			}
 
-			// Skip non-markdown files
-			if !strings.HasSuffix(strings.ToLower(path), ".md") {
-				return nil
+			line := fmt.Sprintf("   %s: %s", lineSpan(block), describeBlock(block))
+			if done[block.ID] {
+				line += " ✅"
 			}
-
-			// Skip ai-dsl files
-			if strings.Contains(path, "ai-dsl.sh") || strings.Contains(path, "ai-dsl.md") {
-				return nil
+			lines = append(lines, line)
+			if len(block.Prerequisites) > 0 {
+				lines = append(lines, fmt.Sprintf("      ⏳ Prerequisites: %s", strings.Join(block.Prerequisites, ", ")))
 			}
+		}
 
-			// Read file content
-			content, err := ioutil.ReadFile(path)
-			if err != nil {
-				return nil
+		if len(lines) > 0 {
+			foundAny = true
+			fmt.Printf("📄 %s\n", doc.Path)
+			for _, line := range lines {
+				fmt.Println(line)
 			}
+			fmt.Println()
+		}
+	}
 
-			// Check for DONE markers
-			doneRegex := regexp.MustCompile(`::DONE:\s*([0-9, ]+)\s*::`)
-			if !doneRegex.MatchString(string(content)) {
-				return nil
-			}
+	if !foundAny {
+		fmt.Println("ℹ️  No DSL collaboration tasks found.")
+		fmt.Println()
+		fmt.Println("💡 To create collaboration tasks, use:")
+		for i, name := range agents {
+			fmt.Printf("   ::%s:%d:: Task description for %s ::\n", name, i+1, name)
+		}
+		fmt.Println("   ::DONE:1,2:: Mark tasks 1 and 2 as completed ::")
+	}
 
-			fmt.Printf("📄 Processing: %s\n", path)
-
-			// Extract DONE IDs
-			doneMatches := doneRegex.FindAllStringSubmatch(string(content), -1)
-			var allIDs []string
-
-			for _, match := range doneMatches {
-				if len(match) > 1 {
-					// Split comma-separated IDs
-					ids := strings.Split(match[1], ",")
-					for _, id := range ids {
-						id = strings.TrimSpace(id)
-						if id != "" {
-							allIDs = append(allIDs, id)
-						}
-					}
-				}
-			}
+	fmt.Println("==========================================")
+	fmt.Println("✅ Search complete.")
+}
 
-			if len(allIDs) == 0 {
-				fmt.Println("   ⚠️  No valid IDs found in DONE markers")
-				return nil
-			}
+// completedBlocks returns the blocks clean removes from a file: every DONE
+// marker and the blocks of configured agents whose IDs they list
+func completedBlocks(doc CollabDoc, agents []string) []CollabBlock {
+	done := doc.doneIDs()
+	var blocks []CollabBlock
+	for _, block := range doc.Blocks {
+		if block.IsDone() || containsString(agents, block.Agent) && done[block.ID] && !block.Unterminated {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
 
-			// Remove task blocks for each ID
-			updatedContent := string(content)
-			for _, id := range allIDs {
-				if _, err := strconv.Atoi(id); err != nil {
-					fmt.Printf("   ⚠️  Invalid ID '%s', skipping\n", id)
-					continue
-				}
+func cleanCompletedTasks(searchPaths []string, agents []string, dryRun bool) error {
+	if dryRun {
+		fmt.Println("🧹 Cleaning completed collaboration tasks (dry run, nothing is written)...")
+	} else {
+		fmt.Println("🧹 Cleaning completed collaboration tasks...")
+	}
+	fmt.Println("==========================================")
+	fmt.Println("Path(s):", strings.Join(searchPaths, ", "))
+	fmt.Println()
 
-				fmt.Printf("   🗑️  Removing blocks for ID: %s\n", id)
+	processedFiles := 0
+	var backup *CollabBackup
 
-				// Remove LARS and GEMINI blocks for this ID
-				taskRegex := regexp.MustCompile(fmt.Sprintf(`::(LARS|GEMINI):\s*%s\s*::.*?::\s*`, regexp.QuoteMeta(id)))
-				updatedContent = taskRegex.ReplaceAllString(updatedContent, "")
-			}
+	for _, doc := range parseCollabFiles(searchPaths) {
+		if len(doc.doneIDs()) == 0 {
+			continue
+		}
 
-			// Remove DONE lines
-			updatedContent = doneRegex.ReplaceAllString(updatedContent, "")
+		fmt.Printf("📄 Processing: %s\n", doc.Path)
 
-			// Write back to file
-			err = ioutil.WriteFile(path, []byte(updatedContent), info.Mode())
-			if err != nil {
-				fmt.Printf("   ❌ Error writing file: %v\n", err)
-				return nil
+		// Cutting blocks out of a file whose markers don't parse could remove
+		// the wrong text, so such files are left alone
+		if len(doc.Problems) > 0 {
+			for _, problem := range doc.Problems {
+				fmt.Printf("   ❌ Line %d: %s\n", problem.Line, problem.Message)
 			}
+			fmt.Println("   ⚠️  Skipped: fix the markers above first")
+			continue
+		}
 
-			processedFiles++
-			fmt.Println("   ✅ Cleanup complete")
+		blocks := completedBlocks(doc, agents)
+		for _, block := range blocks {
+			if !block.IsDone() {
+				fmt.Printf("   🗑️  Removing ::%s:%s:: (line %s)\n", block.Agent, block.ID, lineSpan(block))
+			}
+		}
 
-			return nil
-		})
+		updatedContent := removeCollabBlocks(doc.content, blocks)
+		if dryRun {
+			fmt.Println()
+			fmt.Print(unifiedDiff(doc.Path, doc.Path, doc.content, updatedContent))
+			fmt.Println()
+			processedFiles++
+			continue
+		}
 
+		info, err := os.Stat(doc.Path)
 		if err != nil {
-			fmt.Printf("⚠️  Error walking path %s: %v\n", searchPath, err)
+			fmt.Printf("   ❌ Error reading file: %v\n", err)
+			continue
+		}
+
+		// Back up the original before the first change
+		if backup == nil {
+			if backup, err = newCollabBackup(); err != nil {
+				return err
+			}
+			defer backup.discardIfEmpty()
 		}
+		if err := backup.add(doc.Path, []byte(doc.content), []byte(updatedContent)); err != nil {
+			fmt.Printf("   ❌ Error backing up file, left unchanged: %v\n", err)
+			continue
+		}
+
+		// Write back to file
+		if err := replaceFile(doc.Path, []byte(updatedContent), info.Mode()); err != nil {
+			fmt.Printf("   ❌ Error writing file: %v\n", err)
+			continue
+		}
+
+		processedFiles++
+		fmt.Println("   ✅ Cleanup complete")
 	}
 
 	fmt.Println("==========================================")
+	if dryRun {
+		fmt.Printf("✅ %d files would change\n", processedFiles)
+		if processedFiles > 0 {
+			fmt.Println()
+			fmt.Println("💡 Next steps:")
+			fmt.Printf("   dppm collab clean %s  # Apply the changes\n", strings.Join(searchPaths, " "))
+		}
+		return nil
+	}
 	fmt.Printf("✅ Processed %d files\n", processedFiles)
+	if backup != nil && len(backup.Files) > 0 {
+		fmt.Printf("🗄️  Backup: %s\n", backup.dir)
+	}
 
 	if processedFiles > 0 {
 		fmt.Println()
 		fmt.Println("💡 Next steps:")
 		fmt.Println("   dppm collab find     # Verify cleanup")
+		fmt.Println("   dppm collab restore  # Undo this clean")
 		fmt.Println("   dppm wiki \"collaboration workflow\"  # Learn more")
 	}
+	return nil
 }
 
 func showCollabWikiIndex() {
@@ -423,7 +552,7 @@ Basic Format:
   ::AI_NAME:ID:: task content ::
 
 Where:
-  • AI_NAME: LARS, GEMINI, or DONE
+  • AI_NAME: a configured agent tag (default LARS, GEMINI) or DONE
   • ID: Unique number for the task
   • task content: Description of work to be done
 
@@ -476,6 +605,18 @@ Dependencies:
   Task: Integrate authentication with the main application
   ::
 
+📐 PARSING RULES:
+
+  • A block ends at the first :: with whitespace or a line boundary on
+    both sides, so std::vector or Queue::push() in the text is safe
+  • "Key: value" lines at the start of a line are block metadata; every
+    number on the Prerequisites line is a prerequisite, "1-6" is a range
+  • Markers in fenced code blocks and inline code spans are ignored
+  • A block without its closing :: is reported with its line number and
+    the file is left alone by clean
+  • DONE markers end at the end of their header or at a :: later on the
+    same line
+
 📊 TASK LIFECYCLE:
 
 Creation:
@@ -493,6 +634,9 @@ Find all tasks:
   dppm collab find              # Current directory
   dppm collab find docs/        # Specific directory
 
+What to do next:
+  dppm collab next --agent LARS # Ready tasks, prerequisites resolved
+
 Clean completed:
   dppm collab clean             # Remove DONE tasks
   dppm collab clean --dry-run   # Preview changes
@@ -1022,7 +1166,7 @@ Project-Level Collaboration:
 
 Documentation-Driven Development:
   • Store collaboration tasks in project docs
-  • Link AI tasks to DPPM tasks and phases
+  • Link AI tasks to DPPM tasks: dppm collab sync --project X --phase P1
   • Archive completed work in project history
 
 🏗️ RECOMMENDED PROJECT STRUCTURE:
@@ -1209,8 +1353,16 @@ Quick Setup for New Project:
 }
 
 func init() {
+	collabFindCmd.Flags().StringP("project", "p", "", "Use the collab agents configured for this project")
+	collabFindCmd.Flags().String("agent", "", "Only show the open markers of this agent")
+	collabCleanCmd.Flags().StringP("project", "p", "", "Use the collab agents configured for this project")
+	collabCleanCmd.Flags().Bool("dry-run", false, "Show a diff of what would be removed without changing files")
+
 	collabCmd.AddCommand(collabFindCmd)
 	collabCmd.AddCommand(collabCleanCmd)
+	collabCmd.AddCommand(collabRestoreCmd)
+	collabCmd.AddCommand(collabSyncCmd)
+	collabCmd.AddCommand(collabNextCmd)
 	collabCmd.AddCommand(collabWikiCmd)
 	rootCmd.AddCommand(collabCmd)
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CollabConfig configures the collaboration markers, either per project in
+// project.yaml or per user in ~/.dppm/config.yaml:
+//
+//	collab:
+//	  agents: [CLAUDE, CODEX, GEMINI, REVIEW]
+type CollabConfig struct {
+	Agents []string `yaml:"agents,omitempty" json:"agents,omitempty"`
+}
+
+// defaultCollabAgents are the agent tags used when nothing is configured
+var defaultCollabAgents = []string{"LARS", "GEMINI"}
+
+// doneMarker is the reserved tag that marks collaboration tasks as completed
+const doneMarker = "DONE"
+
+// agentNameRegex restricts agent tags to upper-case marker names like CODEX or QA_BOT
+var agentNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// collabAgents returns the agent tags for a project: the project's collab
+// section, then the user's config, then the defaults
+func collabAgents(projectID string) ([]string, error) {
+	agents := defaultCollabAgents
+	source := "defaults"
+
+	if userAgents := loadUserConfig().Collab.Agents; len(userAgents) > 0 {
+		agents, source = userAgents, "~/.dppm/config.yaml"
+	}
+	if projectID != "" {
+		project, err := store.LoadProject(projectID)
+		if err != nil {
+			return nil, err
+		}
+		if project.Collab != nil && len(project.Collab.Agents) > 0 {
+			agents, source = project.Collab.Agents, "project "+projectID
+		}
+	}
+
+	if err := validateAgentNames(agents); err != nil {
+		return nil, fmt.Errorf("invalid collab agents in %s: %v", source, err)
+	}
+	return agents, nil
+}
+
+// validateAgentNames checks configured agent tags can be used as markers
+func validateAgentNames(agents []string) error {
+	seen := make(map[string]bool)
+	for _, agent := range agents {
+		if !agentNameRegex.MatchString(agent) {
+			return fmt.Errorf("'%s' must be upper-case letters, digits or underscores, starting with a letter", agent)
+		}
+		if agent == doneMarker {
+			return fmt.Errorf("'%s' is reserved for completion markers", doneMarker)
+		}
+		if seen[agent] {
+			return fmt.Errorf("'%s' is listed twice", agent)
+		}
+		seen[agent] = true
+	}
+	return nil
+}
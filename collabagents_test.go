@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setUserConfig replaces ~/.dppm/config.yaml for the duration of a test
+func setUserConfig(t *testing.T, config UserConfig) {
+	t.Helper()
+	userConfigOnce.Do(func() {})
+	saved := userConfig
+	userConfig = config
+	t.Cleanup(func() { userConfig = saved })
+}
+
+func TestValidateAgentNames(t *testing.T) {
+	tests := []struct {
+		agents  []string
+		message string
+	}{
+		{[]string{"CODEX", "QA_BOT", "R2D2"}, ""},
+		{[]string{"codex"}, "'codex' must be upper-case"},
+		{[]string{"2ND"}, "'2ND' must be upper-case"},
+		{[]string{"QA-BOT"}, "'QA-BOT' must be upper-case"},
+		{[]string{"LARS", "DONE"}, "'DONE' is reserved"},
+		{[]string{"LARS", "GEMINI", "LARS"}, "'LARS' is listed twice"},
+	}
+	for _, test := range tests {
+		err := validateAgentNames(test.agents)
+		if test.message == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error %v", test.agents, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("%v: error %v, want %q", test.agents, err, test.message)
+		}
+	}
+}
+
+func TestCollabAgentsPrecedence(t *testing.T) {
+	store = NewMemoryStore()
+	for _, project := range []Project{
+		{ID: "plain"},
+		{ID: "team", Collab: &CollabConfig{Agents: []string{"CODEX", "REVIEW"}}},
+		{ID: "broken", Collab: &CollabConfig{Agents: []string{"codex"}}},
+	} {
+		project := project
+		if err := store.SaveProject(&project); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	setUserConfig(t, UserConfig{})
+	if agents, err := collabAgents(""); err != nil || !reflect.DeepEqual(agents, defaultCollabAgents) {
+		t.Errorf("without config: %v, %v", agents, err)
+	}
+
+	setUserConfig(t, UserConfig{Collab: CollabConfig{Agents: []string{"CLAUDE", "GEMINI"}}})
+	tests := []struct {
+		projectID string
+		want      []string
+		message   string
+	}{
+		{"", []string{"CLAUDE", "GEMINI"}, ""},
+		{"plain", []string{"CLAUDE", "GEMINI"}, ""},
+		{"team", []string{"CODEX", "REVIEW"}, ""},
+		{"broken", nil, "invalid collab agents in project broken"},
+		{"missing", nil, "not found"},
+	}
+	for _, test := range tests {
+		agents, err := collabAgents(test.projectID)
+		if test.message != "" {
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Errorf("project %q: error %v, want %q", test.projectID, err, test.message)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(agents, test.want) {
+			t.Errorf("project %q: %v, %v, want %v", test.projectID, agents, err, test.want)
+		}
+	}
+
+	setUserConfig(t, UserConfig{Collab: CollabConfig{Agents: []string{"DONE"}}})
+	if _, err := collabAgents(""); err == nil || !strings.Contains(err.Error(), "in ~/.dppm/config.yaml") {
+		t.Errorf("reserved tag in the user config: %v", err)
+	}
+}
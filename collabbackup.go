@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CollabBackup records the files one 'dppm collab clean' changed. Backups live
+// in ~/.dppm/collab-backups/<id>/ next to a manifest.yaml of this type.
+type CollabBackup struct {
+	ID      string             `yaml:"id" json:"id"`
+	Created string             `yaml:"created" json:"created"`
+	Files   []CollabBackupFile `yaml:"files" json:"files"`
+
+	dir string
+}
+
+// CollabBackupFile is one backed up file
+type CollabBackupFile struct {
+	Path   string `yaml:"path" json:"path"`
+	Backup string `yaml:"backup" json:"backup"`
+	// Checksum of the file as clean left it, to notice later edits
+	CleanedSHA256 string `yaml:"cleaned_sha256" json:"cleaned_sha256"`
+}
+
+// getCollabBackupDir returns the folder holding the clean backups
+func getCollabBackupDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".dppm", "collab-backups"), nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newCollabBackup creates an empty timestamped backup folder
+func newCollabBackup() (*CollabBackup, error) {
+	root, err := getCollabBackupDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup folder: %v", err)
+	}
+
+	now := time.Now()
+	id := now.Format("20060102-150405")
+	for n := 2; ; n++ {
+		err := os.Mkdir(filepath.Join(root, id), 0755)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create backup folder: %v", err)
+		}
+		id = fmt.Sprintf("%s-%d", now.Format("20060102-150405"), n)
+	}
+
+	return &CollabBackup{ID: id, Created: now.Format(time.RFC3339), Files: []CollabBackupFile{}, dir: filepath.Join(root, id)}, nil
+}
+
+// add stores the original content of path before clean writes cleaned to it.
+// The manifest is saved after every file so an interrupted clean can still be
+// rolled back.
+func (b *CollabBackup) add(path string, original, cleaned []byte) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%03d-%s.bak", len(b.Files)+1, filepath.Base(path))
+	if err := writeFileAtomic(filepath.Join(b.dir, name), original); err != nil {
+		return err
+	}
+	b.Files = append(b.Files, CollabBackupFile{Path: absPath, Backup: name, CleanedSHA256: sha256Hex(cleaned)})
+	return writeYAMLFile(filepath.Join(b.dir, "manifest.yaml"), b)
+}
+
+// discardIfEmpty removes the folder of a backup no file was added to
+func (b *CollabBackup) discardIfEmpty() {
+	if len(b.Files) == 0 {
+		os.RemoveAll(b.dir)
+	}
+}
+
+// listCollabBackups returns the backups, newest first
+func listCollabBackups() ([]*CollabBackup, error) {
+	root, err := getCollabBackupDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []*CollabBackup
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		var backup CollabBackup
+		if err := readYAMLFile(filepath.Join(root, entry.Name(), "manifest.yaml"), &backup); err != nil {
+			continue
+		}
+		backup.dir = filepath.Join(root, entry.Name())
+		backups = append(backups, &backup)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return parseTimestamp(backups[i].Created).After(parseTimestamp(backups[j].Created)) ||
+			backups[i].Created == backups[j].Created && backups[i].ID > backups[j].ID
+	})
+	return backups, nil
+}
+
+var collabRestoreCmd = &cobra.Command{
+	Use:   "restore [backup-id]",
+	Short: "Roll back a collab clean from its backup",
+	Long: `Restore Files From a Clean Backup
+
+Every 'dppm collab clean' that changes files first copies them to
+~/.dppm/collab-backups/<timestamp>/. Restore puts the originals back,
+by default from the most recent clean.
+
+Files edited after the clean are left alone unless --force is given, so
+later work is not overwritten. A backup is deleted once all of its files
+are restored; while files remain, restore exits with an error.
+
+Examples:
+  dppm collab restore                     # Roll back the last clean
+  dppm collab restore --list              # Show available backups
+  dppm collab restore 20261016-142501     # Roll back a specific clean
+  dppm collab restore --force             # Also overwrite edited files`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+		force, _ := cmd.Flags().GetBool("force")
+
+		backups, err := listCollabBackups()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if list {
+			if printStructured(backups) {
+				return
+			}
+			if len(backups) == 0 {
+				fmt.Println("ℹ️  No collab clean backups found.")
+				return
+			}
+			fmt.Println("🗄️  Collab clean backups (newest first):")
+			for _, backup := range backups {
+				fmt.Printf("  %s  %d file(s), %s\n", backup.ID, len(backup.Files), parseTimestamp(backup.Created).Format("2006-01-02 15:04"))
+				for _, file := range backup.Files {
+					fmt.Printf("      %s\n", file.Path)
+				}
+			}
+			return
+		}
+
+		if len(backups) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: No collab clean backups found")
+			os.Exit(1)
+		}
+		backup := backups[0]
+		if len(args) > 0 {
+			backup = nil
+			for _, candidate := range backups {
+				if candidate.ID == args[0] {
+					backup = candidate
+				}
+			}
+			if backup == nil {
+				fmt.Fprintf(os.Stderr, "Error: Backup '%s' not found (see 'dppm collab restore --list')\n", args[0])
+				os.Exit(1)
+			}
+		}
+
+		if err := restoreCollabBackup(backup, force); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// restoreCollabBackup writes the backed up files back in place. Files that
+// could not be restored stay in the backup and are reported as an error.
+func restoreCollabBackup(backup *CollabBackup, force bool) error {
+	fmt.Printf("⏪ Restoring collab clean %s...\n", backup.ID)
+	fmt.Println("==========================================")
+
+	restored := 0
+	var remaining []CollabBackupFile
+	for _, file := range backup.Files {
+		original, err := ioutil.ReadFile(filepath.Join(backup.dir, file.Backup))
+		if err != nil {
+			fmt.Printf("   ❌ %s: backup unreadable: %v\n", file.Path, err)
+			remaining = append(remaining, file)
+			continue
+		}
+
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(file.Path); err == nil {
+			mode = info.Mode()
+			current, err := ioutil.ReadFile(file.Path)
+			if err == nil && sha256Hex(current) != file.CleanedSHA256 && !force {
+				fmt.Printf("   ⚠️  %s: changed since the clean, skipped (use --force to overwrite)\n", file.Path)
+				remaining = append(remaining, file)
+				continue
+			}
+		}
+
+		if err := replaceFile(file.Path, original, mode); err != nil {
+			fmt.Printf("   ❌ %s: %v\n", file.Path, err)
+			remaining = append(remaining, file)
+			continue
+		}
+		fmt.Printf("   ✅ %s\n", file.Path)
+		restored++
+	}
+
+	fmt.Println("==========================================")
+	fmt.Printf("✅ Restored %d files\n", restored)
+	if len(remaining) > 0 {
+		// Keep only what is still to be restored
+		backup.Files = remaining
+		if err := writeYAMLFile(filepath.Join(backup.dir, "manifest.yaml"), backup); err != nil {
+			fmt.Printf("❌ Error updating backup manifest: %v\n", err)
+		}
+		return fmt.Errorf("%d file(s) not restored; the backup is kept in %s", len(remaining), backup.dir)
+	}
+	os.RemoveAll(backup.dir)
+	return nil
+}
+
+// replaceFile atomically replaces path with data and gives it mode
+func replaceFile(path string, data []byte, mode os.FileMode) error {
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	if mode.Perm() != 0644 {
+		return os.Chmod(path, mode.Perm())
+	}
+	return nil
+}
+
+func init() {
+	collabRestoreCmd.Flags().Bool("list", false, "List the available backups")
+	collabRestoreCmd.Flags().Bool("force", false, "Restore files even if they changed since the clean")
+}
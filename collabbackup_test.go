@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cleanTestPlan = "# Plan\n\n::LARS:1::\nBuild the API\n::\n\n::DONE:1::\n\nKeep this.\n"
+
+func TestCleanAndRestore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plan.md")
+	if err := os.WriteFile(path, []byte(cleanTestPlan), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cleanCompletedTasks([]string{dir}, defaultCollabAgents, false); err != nil {
+		t.Fatal(err)
+	}
+	cleaned, _ := os.ReadFile(path)
+	if strings.Contains(string(cleaned), "::") || !strings.Contains(string(cleaned), "Keep this.") {
+		t.Errorf("cleaned file:\n%s", cleaned)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("clean changed the file mode to %v", info.Mode().Perm())
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, ".*tmp-*")); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+
+	backups, err := listCollabBackups()
+	if err != nil || len(backups) != 1 || len(backups[0].Files) != 1 {
+		t.Fatalf("backups after clean: %v, %v", backups, err)
+	}
+
+	// An edit after the clean is not overwritten without --force
+	if err := os.WriteFile(path, []byte("Edited\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := restoreCollabBackup(backups[0], false); err == nil || !strings.Contains(err.Error(), "1 file(s) not restored") {
+		t.Errorf("restoring over an edited file: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "Edited\n" {
+		t.Errorf("edited file overwritten:\n%s", content)
+	}
+	if _, err := os.Stat(backups[0].dir); err != nil {
+		t.Errorf("backup removed although a file was not restored: %v", err)
+	}
+
+	if err := restoreCollabBackup(backups[0], true); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != cleanTestPlan {
+		t.Errorf("restored file:\n%s", content)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("restore changed the file mode to %v", info.Mode().Perm())
+	}
+	if _, err := os.Stat(backups[0].dir); !os.IsNotExist(err) {
+		t.Errorf("backup kept after a full restore: %v", err)
+	}
+}
+
+func TestCleanReportsBackupFailure(t *testing.T) {
+	// A file where the backup folder should go makes creating it fail
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, ".dppm"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plan.md")
+	if err := os.WriteFile(path, []byte(cleanTestPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cleanCompletedTasks([]string{dir}, defaultCollabAgents, false); err == nil {
+		t.Error("clean succeeded without a backup")
+	}
+	if content, _ := os.ReadFile(path); string(content) != cleanTestPlan {
+		t.Errorf("file changed without a backup:\n%s", content)
+	}
+}
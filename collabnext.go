@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CollabQueue is the work of one agent: blocks whose prerequisites are all
+// DONE, and blocks still waiting for others
+type CollabQueue struct {
+	Agent   string            `yaml:"agent" json:"agent"`
+	Ready   []CollabQueueItem `yaml:"ready" json:"ready"`
+	Blocked []CollabQueueItem `yaml:"blocked" json:"blocked"`
+}
+
+// CollabQueueItem is one open block in a queue
+type CollabQueueItem struct {
+	File      string   `yaml:"file" json:"file"`
+	Line      int      `yaml:"line" json:"line"`
+	ID        string   `yaml:"id" json:"id"`
+	Summary   string   `yaml:"summary" json:"summary"`
+	Unblocks  int      `yaml:"unblocks,omitempty" json:"unblocks,omitempty"`
+	WaitingOn []string `yaml:"waiting_on,omitempty" json:"waiting_on,omitempty"`
+}
+
+var collabNextCmd = &cobra.Command{
+	Use:   "next [path...]",
+	Short: "Show which collaboration tasks each agent can start now",
+	Long: `Prerequisite-Aware Collaboration Queue
+
+Lists the open blocks of an agent (or of every configured agent) split into
+ready and blocked ones. Marker IDs are only unique within a file: a block
+is done when its own file has a ::DONE:: marker for it, and a prerequisite
+refers to the block with that ID in the same file if there is one. Other
+prerequisites are resolved against the ::DONE:: markers of all searched
+files, so work handed over in another document counts too.
+
+Ready blocks come first, those that other open blocks wait for ahead of the
+rest, then by ID. Blocked blocks are listed with the prerequisites they
+still wait for and who holds them.
+
+Examples:
+  dppm collab next --agent LARS
+  dppm collab next docs/ --project web-app
+  dppm collab next --agent GEMINI --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		searchPaths := []string{"."}
+		if len(args) > 0 {
+			searchPaths = args
+		}
+		projectID, _ := cmd.Flags().GetString("project")
+		agent, _ := cmd.Flags().GetString("agent")
+
+		agents, err := collabAgents(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if agent != "" {
+			agent = strings.ToUpper(agent)
+			if !containsString(agents, agent) {
+				fmt.Fprintf(os.Stderr, "Error: Unknown agent '%s'. Configured agents: %s\n", agent, strings.Join(agents, ", "))
+				os.Exit(1)
+			}
+			agents = []string{agent}
+		}
+
+		docs := parseCollabFiles(searchPaths)
+		queues := buildCollabQueues(docs, agents)
+		if printStructured(queues) {
+			return
+		}
+		printCollabQueues(queues, docs)
+	},
+}
+
+// openCollabBlock is a block that is not DONE yet, with the file it is in
+type openCollabBlock struct {
+	file  string
+	block CollabBlock
+}
+
+// buildCollabQueues sorts each agent's open blocks into ready and blocked
+// ones. Marker IDs are only unique within a file, so a block is done only by a
+// DONE marker in its own file, and a prerequisite names a block of the same
+// file when there is one. Other prerequisites are resolved against the DONE
+// markers and open blocks of all documents.
+func buildCollabQueues(docs []CollabDoc, agents []string) []CollabQueue {
+	doneAnywhere := make(map[string]bool)
+	doneIn := make(map[string]map[string]bool)
+	for _, doc := range docs {
+		doneIn[doc.Path] = doc.doneIDs()
+		for id := range doneIn[doc.Path] {
+			doneAnywhere[id] = true
+		}
+	}
+
+	openIn := make(map[string][]openCollabBlock) // by collabKey
+	openAnywhere := make(map[string][]openCollabBlock)
+	var order []openCollabBlock
+	for _, doc := range docs {
+		for _, block := range doc.Blocks {
+			if block.IsDone() || block.Unterminated || doneIn[doc.Path][block.ID] {
+				continue
+			}
+			entry := openCollabBlock{file: doc.Path, block: block}
+			key := collabKey(doc.Path, block.ID)
+			openIn[key] = append(openIn[key], entry)
+			openAnywhere[block.ID] = append(openAnywhere[block.ID], entry)
+			order = append(order, entry)
+		}
+	}
+
+	// holders returns the open blocks a prerequisite of a block in file waits
+	// for, and whether the prerequisite is done
+	holders := func(file, id string) ([]openCollabBlock, bool) {
+		if doneIn[file][id] {
+			return nil, true
+		}
+		if sameFile := openIn[collabKey(file, id)]; len(sameFile) > 0 {
+			return sameFile, false
+		}
+		if doneAnywhere[id] {
+			return nil, true
+		}
+		return openAnywhere[id], false
+	}
+
+	// How many open blocks wait directly for each block
+	waiters := make(map[string]int)
+	for _, entry := range order {
+		for _, prerequisite := range entry.block.Prerequisites {
+			if prerequisite == entry.block.ID {
+				continue
+			}
+			blocks, _ := holders(entry.file, prerequisite)
+			for _, holder := range blocks {
+				waiters[collabKey(holder.file, holder.block.ID)]++
+			}
+		}
+	}
+
+	var queues []CollabQueue
+	for _, agent := range agents {
+		queue := CollabQueue{Agent: agent, Ready: []CollabQueueItem{}, Blocked: []CollabQueueItem{}}
+		for _, entry := range order {
+			if entry.block.Agent != agent {
+				continue
+			}
+			item := CollabQueueItem{
+				File:     entry.file,
+				Line:     entry.block.StartLine,
+				ID:       entry.block.ID,
+				Summary:  entry.block.Summary(),
+				Unblocks: waiters[collabKey(entry.file, entry.block.ID)],
+			}
+			for _, prerequisite := range entry.block.Prerequisites {
+				if prerequisite == entry.block.ID {
+					continue
+				}
+				blocks, done := holders(entry.file, prerequisite)
+				if !done {
+					item.WaitingOn = append(item.WaitingOn, describePrerequisite(prerequisite, entry.file, blocks))
+				}
+			}
+			if len(item.WaitingOn) == 0 {
+				queue.Ready = append(queue.Ready, item)
+			} else {
+				queue.Blocked = append(queue.Blocked, item)
+			}
+		}
+		sort.SliceStable(queue.Ready, func(i, j int) bool {
+			if queue.Ready[i].Unblocks != queue.Ready[j].Unblocks {
+				return queue.Ready[i].Unblocks > queue.Ready[j].Unblocks
+			}
+			return lessCollabID(queue.Ready[i].ID, queue.Ready[j].ID)
+		})
+		sort.SliceStable(queue.Blocked, func(i, j int) bool {
+			return lessCollabID(queue.Blocked[i].ID, queue.Blocked[j].ID)
+		})
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
+// describePrerequisite names an unfinished prerequisite of a block in file and
+// who holds it, e.g. "3 (LARS)", "4 (GEMINI in api.md)" or "7 (no such marker)"
+func describePrerequisite(id, file string, holders []openCollabBlock) string {
+	if len(holders) == 0 {
+		return id + " (no such marker)"
+	}
+	var names []string
+	for _, holder := range holders {
+		name := holder.block.Agent
+		if holder.file != file {
+			name += " in " + holder.file
+		}
+		names = addString(names, name)
+	}
+	return fmt.Sprintf("%s (%s)", id, strings.Join(names, ", "))
+}
+
+// lessCollabID orders numeric IDs by value and others alphabetically after them
+func lessCollabID(a, b string) bool {
+	numA, errA := strconv.Atoi(a)
+	numB, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return numA < numB
+	case errA == nil || errB == nil:
+		return errA == nil
+	}
+	return a < b
+}
+
+// describeQueueItem shows a queued block as marker, summary and location
+func describeQueueItem(agent string, item CollabQueueItem) string {
+	text := fmt.Sprintf("::%s:%s::", agent, item.ID)
+	if item.Summary != "" {
+		text += " " + truncate(item.Summary, 50)
+	}
+	return fmt.Sprintf("%s  (%s:%d)", text, item.File, item.Line)
+}
+
+func printCollabQueues(queues []CollabQueue, docs []CollabDoc) {
+	for _, doc := range docs {
+		for _, problem := range doc.Problems {
+			fmt.Printf("⚠️  %s:%d: %s\n", doc.Path, problem.Line, problem.Message)
+		}
+	}
+
+	var first *CollabQueueItem
+	var firstAgent string
+	for _, queue := range queues {
+		fmt.Printf("🤖 %s\n", queue.Agent)
+		fmt.Println("==========================================")
+		if len(queue.Ready) == 0 && len(queue.Blocked) == 0 {
+			fmt.Println("ℹ️  No open collaboration tasks")
+			fmt.Println()
+			continue
+		}
+
+		if len(queue.Ready) > 0 {
+			fmt.Println("🟢 Ready:")
+			for i, item := range queue.Ready {
+				line := fmt.Sprintf("  %d. %s", i+1, describeQueueItem(queue.Agent, item))
+				if item.Unblocks > 0 {
+					line += fmt.Sprintf("  → unblocks %d", item.Unblocks)
+				}
+				fmt.Println(line)
+			}
+			if first == nil {
+				first, firstAgent = &queue.Ready[0], queue.Agent
+			}
+		} else {
+			fmt.Println("🟢 Ready: nothing, everything waits for prerequisites")
+		}
+
+		if len(queue.Blocked) > 0 {
+			fmt.Println("🔴 Blocked:")
+			for _, item := range queue.Blocked {
+				fmt.Printf("  %s\n", describeQueueItem(queue.Agent, item))
+				fmt.Printf("     ⏳ waits for %s\n", strings.Join(item.WaitingOn, ", "))
+			}
+		}
+		fmt.Println()
+	}
+
+	if first != nil {
+		fmt.Printf("🎯 HØJRE HEGN - NEXT ACTIONS:\n")
+		fmt.Printf("  # %s: start ::%s:%s:: in %s (line %d)\n", firstAgent, firstAgent, first.ID, first.File, first.Line)
+		fmt.Printf("  # When finished, add ::%s:%s:: to the file, then:\n", doneMarker, first.ID)
+		fmt.Printf("  dppm collab next --agent %s  # See what it unblocked\n", firstAgent)
+	}
+}
+
+func init() {
+	collabNextCmd.Flags().StringP("project", "p", "", "Use the collab agents configured for this project")
+	collabNextCmd.Flags().String("agent", "", "Only show the queue of this agent")
+}
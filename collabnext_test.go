@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// queueIDs lists the ready and blocked blocks of one agent as "file#id"
+func queueIDs(queues []CollabQueue, agent string) (ready, blocked []string) {
+	ready, blocked = []string{}, []string{}
+	for _, queue := range queues {
+		if queue.Agent != agent {
+			continue
+		}
+		for _, item := range queue.Ready {
+			ready = append(ready, collabKey(item.File, item.ID))
+		}
+		for _, item := range queue.Blocked {
+			blocked = append(blocked, collabKey(item.File, item.ID))
+		}
+	}
+	return ready, blocked
+}
+
+func TestBuildCollabQueuesKeepsIDsPerFile(t *testing.T) {
+	docs := []CollabDoc{
+		parseCollabMarkers("a.md", "::GEMINI:1::\nSchema\n::\n\n::DONE:1::\n"),
+		parseCollabMarkers("b.md", "::LARS:1::\nUnrelated work\n::\n\n::LARS:2::\nPrerequisites: 1\n::\n"),
+	}
+	queues := buildCollabQueues(docs, defaultCollabAgents)
+
+	// b.md#1 is not done by a.md's DONE marker, and b.md#2 waits for it
+	ready, blocked := queueIDs(queues, "LARS")
+	if !reflect.DeepEqual(ready, []string{"b.md#1"}) || !reflect.DeepEqual(blocked, []string{"b.md#2"}) {
+		t.Fatalf("LARS ready %v, blocked %v", ready, blocked)
+	}
+	if queues[0].Ready[0].Unblocks != 1 {
+		t.Errorf("b.md#1 unblocks %d, want 1", queues[0].Ready[0].Unblocks)
+	}
+	if want := []string{"1 (LARS)"}; !reflect.DeepEqual(queues[0].Blocked[0].WaitingOn, want) {
+		t.Errorf("b.md#2 waits on %v, want %v", queues[0].Blocked[0].WaitingOn, want)
+	}
+}
+
+func TestBuildCollabQueuesResolvesPrerequisitesAcrossFiles(t *testing.T) {
+	docs := []CollabDoc{
+		parseCollabMarkers("api.md", "::GEMINI:4::\nEndpoints\n::\n\n::GEMINI:5::\nDocs\n::\n\n::DONE:5::\n"),
+		parseCollabMarkers("ui.md", "::LARS:1::\nForm\nPrerequisites: 4\n::\n\n::LARS:2::\nHelp page\nPrerequisites: 5\n::\n"),
+	}
+	queues := buildCollabQueues(docs, defaultCollabAgents)
+
+	ready, blocked := queueIDs(queues, "LARS")
+	if !reflect.DeepEqual(ready, []string{"ui.md#2"}) || !reflect.DeepEqual(blocked, []string{"ui.md#1"}) {
+		t.Fatalf("LARS ready %v, blocked %v", ready, blocked)
+	}
+	if want := []string{"4 (GEMINI in api.md)"}; !reflect.DeepEqual(queues[0].Blocked[0].WaitingOn, want) {
+		t.Errorf("ui.md#1 waits on %v, want %v", queues[0].Blocked[0].WaitingOn, want)
+	}
+
+	ready, _ = queueIDs(queues, "GEMINI")
+	if !reflect.DeepEqual(ready, []string{"api.md#4"}) || queues[1].Ready[0].Unblocks != 1 {
+		t.Errorf("GEMINI ready %v", queues[1].Ready)
+	}
+}
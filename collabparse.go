@@ -0,0 +1,369 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CollabBlock is one parsed collaboration marker. Agent blocks run from their
+// ::AGENT:ID:: header to the closing ::, which may be lines further down.
+// DONE markers list completed IDs and may carry a one-line comment:
+//
+//	::LARS:1::
+//	Prerequisites: Task 3 completed
+//	Implement the thing
+//	::
+//
+//	::DONE:1,2:: Shipped ::
+type CollabBlock struct {
+	Agent         string            `yaml:"agent" json:"agent"`
+	ID            string            `yaml:"id,omitempty" json:"id,omitempty"`
+	DoneIDs       []string          `yaml:"done_ids,omitempty" json:"done_ids,omitempty"`
+	Body          string            `yaml:"body,omitempty" json:"body,omitempty"`
+	Prerequisites []string          `yaml:"prerequisites,omitempty" json:"prerequisites,omitempty"`
+	Metadata      map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	StartLine     int               `yaml:"start_line" json:"start_line"`
+	EndLine       int               `yaml:"end_line" json:"end_line"`
+
+	// Byte offsets of the whole block in the file, end exclusive
+	Start int `yaml:"-" json:"-"`
+	End   int `yaml:"-" json:"-"`
+	// Unterminated blocks have no closing :: and are never removed
+	Unterminated bool `yaml:"unterminated,omitempty" json:"unterminated,omitempty"`
+}
+
+// CollabProblem is a syntax problem found while parsing markers
+type CollabProblem struct {
+	Line    int    `yaml:"line" json:"line"`
+	Message string `yaml:"message" json:"message"`
+}
+
+// CollabDoc is the result of parsing one markdown file
+type CollabDoc struct {
+	Path     string          `yaml:"path" json:"path"`
+	Blocks   []CollabBlock   `yaml:"blocks" json:"blocks"`
+	Problems []CollabProblem `yaml:"problems,omitempty" json:"problems,omitempty"`
+
+	content string // the parsed text, which block offsets refer to
+}
+
+// IsDone reports whether the block is a DONE marker
+func (b CollabBlock) IsDone() bool {
+	return b.Agent == doneMarker
+}
+
+// Summary is the first non-metadata line of the body
+func (b CollabBlock) Summary() string {
+	for _, line := range strings.Split(b.Body, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && metadataLineRegex.FindStringSubmatch(line) == nil {
+			return line
+		}
+	}
+	if task, ok := b.Metadata["Task"]; ok {
+		return task
+	}
+	return ""
+}
+
+// doneIDs collects the IDs of all DONE markers in the document
+func (d CollabDoc) doneIDs() map[string]bool {
+	done := make(map[string]bool)
+	for _, block := range d.Blocks {
+		for _, id := range block.DoneIDs {
+			done[id] = true
+		}
+	}
+	return done
+}
+
+var (
+	// metadataLineRegex matches "Key: value" lines such as "Prerequisites: Task 1 completed"
+	metadataLineRegex = regexp.MustCompile(`^([A-Z][A-Za-z ]{0,30}):(\s.*)?$`)
+	// prerequisiteIDRegex finds the task IDs and ranges like "1-6" in a Prerequisites line
+	prerequisiteIDRegex = regexp.MustCompile(`\b([0-9]+)(?:\s*(?:-|–|to)\s*([0-9]+))?\b`)
+	collabIDRegex       = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// collabScanner walks the content of a markdown file
+type collabScanner struct {
+	src        string
+	lineStarts []int
+	fenced     []bool
+	doc        CollabDoc
+}
+
+// parseCollabMarkers parses all collaboration markers in content. Markers in
+// fenced code blocks and inline code spans are ignored. A closing :: must be
+// surrounded by whitespace or line boundaries, so "std::vector" in a body does
+// not end the block.
+func parseCollabMarkers(path, content string) CollabDoc {
+	s := &collabScanner{src: content, doc: CollabDoc{Path: path, Blocks: []CollabBlock{}, content: content}}
+	s.lineStarts = append(s.lineStarts, 0)
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+	s.markFences()
+
+	pos := 0
+	for pos < len(content) {
+		start, headerEnd, agent, id, ok := s.nextHeader(pos)
+		if !ok {
+			break
+		}
+		if agent == doneMarker {
+			pos = s.parseDone(start, headerEnd, id)
+		} else {
+			pos = s.parseAgentBlock(start, headerEnd, agent, id)
+		}
+	}
+	return s.doc
+}
+
+// lineOf returns the 1-based line number of a byte offset
+func (s *collabScanner) lineOf(offset int) int {
+	return sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > offset })
+}
+
+// lineEnd returns the offset of the newline ending the line at offset, or len(src)
+func (s *collabScanner) lineEnd(offset int) int {
+	if i := strings.IndexByte(s.src[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(s.src)
+}
+
+// inFence reports whether offset lies inside a fenced code block; the fence
+// lines themselves count as inside
+func (s *collabScanner) inFence(offset int) bool {
+	return s.fenced[s.lineOf(offset)-1]
+}
+
+// markFences records which lines belong to fenced code blocks
+func (s *collabScanner) markFences() {
+	s.fenced = make([]bool, len(s.lineStarts))
+	inside := false
+	for i, lineStart := range s.lineStarts {
+		line := strings.TrimSpace(s.src[lineStart:s.lineEnd(lineStart)])
+		isFence := strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+		s.fenced[i] = inside || isFence
+		if isFence {
+			inside = !inside
+		}
+	}
+}
+
+// inInlineCode reports whether offset lies inside a `code span` on its line
+func (s *collabScanner) inInlineCode(offset int) bool {
+	lineStart := s.lineStarts[s.lineOf(offset)-1]
+	return strings.Count(s.src[lineStart:offset], "`")%2 == 1
+}
+
+// header lexes ::TAG:ID:: at offset, returning its end, tag and ID
+func (s *collabScanner) header(offset int) (end int, tag, id string, ok bool) {
+	src := s.src
+	if !strings.HasPrefix(src[offset:], "::") {
+		return 0, "", "", false
+	}
+	i := offset + 2
+	tagStart := i
+	for i < len(src) && (src[i] >= 'A' && src[i] <= 'Z' || i > tagStart && (src[i] >= '0' && src[i] <= '9' || src[i] == '_')) {
+		i++
+	}
+	if i == tagStart || i >= len(src) || src[i] != ':' {
+		return 0, "", "", false
+	}
+	tag = src[tagStart:i]
+
+	closeAt := strings.Index(src[i+1:], "::")
+	if closeAt < 0 {
+		return 0, "", "", false
+	}
+	id = strings.TrimSpace(src[i+1 : i+1+closeAt])
+	if id == "" || strings.ContainsAny(id, "\n:") {
+		return 0, "", "", false
+	}
+	return i + 1 + closeAt + 2, tag, id, true
+}
+
+// nextHeader finds the next marker header at or after pos outside code
+func (s *collabScanner) nextHeader(pos int) (start, end int, tag, id string, ok bool) {
+	for {
+		i := strings.Index(s.src[pos:], "::")
+		if i < 0 {
+			return 0, 0, "", "", false
+		}
+		start = pos + i
+		if end, tag, id, ok = s.header(start); ok && !s.inFence(start) && !s.inInlineCode(start) {
+			return start, end, tag, id, true
+		}
+		pos = start + 1
+	}
+}
+
+// isTerminator reports whether the :: at offset closes a block
+func (s *collabScanner) isTerminator(offset int) bool {
+	if !strings.HasPrefix(s.src[offset:], "::") {
+		return false
+	}
+	before := offset == 0 || strings.ContainsRune(" \t\n", rune(s.src[offset-1]))
+	after := offset+2 >= len(s.src) || strings.ContainsRune(" \t\r\n", rune(s.src[offset+2]))
+	return before && after
+}
+
+// parseDone handles ::DONE:ids:: with an optional comment closed on the same line
+func (s *collabScanner) parseDone(start, headerEnd int, ids string) int {
+	block := CollabBlock{Agent: doneMarker, Start: start, End: headerEnd, StartLine: s.lineOf(start)}
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		if !collabIDRegex.MatchString(id) {
+			s.problem(start, "invalid ID '"+id+"' in DONE marker")
+			continue
+		}
+		block.DoneIDs = append(block.DoneIDs, id)
+	}
+
+	lineEnd := s.lineEnd(headerEnd)
+	for i := headerEnd; i < lineEnd; i++ {
+		if s.isTerminator(i) {
+			block.Body = strings.TrimSpace(s.src[headerEnd:i])
+			block.End = i + 2
+			break
+		}
+	}
+	block.EndLine = s.lineOf(block.End - 1)
+	s.doc.Blocks = append(s.doc.Blocks, block)
+	return block.End
+}
+
+// parseAgentBlock reads the body of ::AGENT:ID:: up to its closing ::. Another
+// header at the start of a line before the closing :: means the block was
+// never closed.
+func (s *collabScanner) parseAgentBlock(start, headerEnd int, agent, id string) int {
+	block := CollabBlock{Agent: agent, ID: id, Start: start, StartLine: s.lineOf(start)}
+	if !collabIDRegex.MatchString(id) {
+		s.problem(start, "invalid ID '"+id+"' in "+agent+" marker")
+	}
+
+	bodyEnd := -1
+	next := len(s.src)
+	for i := headerEnd; i < len(s.src); i++ {
+		if s.src[i] != ':' || s.inFence(i) {
+			continue
+		}
+		if s.isTerminator(i) {
+			bodyEnd = i
+			break
+		}
+		if _, _, _, ok := s.header(i); ok && s.atLineStart(i) {
+			next = i
+			break
+		}
+	}
+
+	if bodyEnd < 0 {
+		block.Unterminated = true
+		block.Body = strings.TrimSpace(s.src[headerEnd:next])
+		block.End = headerEnd + len(strings.TrimRight(s.src[headerEnd:next], " \t\r\n"))
+		s.problem(start, "::"+agent+":"+id+":: is never closed with ::")
+	} else {
+		block.Body = strings.TrimSpace(s.src[headerEnd:bodyEnd])
+		block.End = bodyEnd + 2
+	}
+	block.EndLine = s.lineOf(block.End - 1)
+	block.Metadata, block.Prerequisites = parseBlockMetadata(block.Body)
+
+	s.doc.Blocks = append(s.doc.Blocks, block)
+	return block.End
+}
+
+// atLineStart reports whether only whitespace precedes offset on its line
+func (s *collabScanner) atLineStart(offset int) bool {
+	lineStart := s.lineStarts[s.lineOf(offset)-1]
+	return strings.TrimSpace(s.src[lineStart:offset]) == ""
+}
+
+func (s *collabScanner) problem(offset int, message string) {
+	s.doc.Problems = append(s.doc.Problems, CollabProblem{Line: s.lineOf(offset), Message: message})
+}
+
+// parseBlockMetadata reads "Key: value" lines of a body and the task IDs of
+// its Prerequisites line
+func parseBlockMetadata(body string) (map[string]string, []string) {
+	var metadata map[string]string
+	var prerequisites []string
+	for _, line := range strings.Split(body, "\n") {
+		match := metadataLineRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		key, value := strings.TrimSpace(match[1]), strings.TrimSpace(match[2])
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		if _, exists := metadata[key]; !exists {
+			metadata[key] = value
+		}
+		if strings.EqualFold(key, "Prerequisites") {
+			for _, match := range prerequisiteIDRegex.FindAllStringSubmatch(value, -1) {
+				from, _ := strconv.Atoi(match[1])
+				to := from
+				if match[2] != "" {
+					to, _ = strconv.Atoi(match[2])
+				}
+				if to < from || to-from > 100 {
+					to = from
+				}
+				for id := from; id <= to; id++ {
+					prerequisites = addString(prerequisites, strconv.Itoa(id))
+				}
+			}
+		}
+	}
+	return metadata, prerequisites
+}
+
+// removeCollabBlocks cuts blocks out of content. A block standing on lines of
+// its own takes those lines with it, together with one surrounding blank
+// line, so no gaps are left behind.
+func removeCollabBlocks(content string, blocks []CollabBlock) string {
+	sorted := append([]CollabBlock(nil), blocks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start > sorted[j].Start })
+
+	for _, block := range sorted {
+		start, end := block.Start, block.End
+
+		lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+		lineEnd := len(content)
+		if i := strings.IndexByte(content[end:], '\n'); i >= 0 {
+			lineEnd = end + i
+		}
+		ownLines := strings.TrimSpace(content[lineStart:start]) == "" && strings.TrimSpace(content[end:lineEnd]) == ""
+
+		if ownLines {
+			start = lineStart
+			end = lineEnd
+			if end < len(content) {
+				end++ // the newline
+			}
+			blankBefore := start == 1 && content[0] == '\n' || start >= 2 && content[start-2] == '\n'
+			switch {
+			case (start == 0 || blankBefore) && strings.HasPrefix(content[end:], "\n"):
+				end++
+			case blankBefore && end == len(content):
+				start-- // don't leave a blank line at the end of the file
+			}
+		} else {
+			// Inline marker: drop the space separating it from the text before
+			for start > lineStart && (content[start-1] == ' ' || content[start-1] == '\t') {
+				start--
+			}
+		}
+		content = content[:start] + content[end:]
+	}
+	return content
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the .golden files of testdata/collab from the current output")
+
+// TestCollabCorpus runs the parser and clean over every file in
+// testdata/collab. NAME.md.golden is the file as 'dppm collab clean' leaves
+// it with the default agents; NAME.md.parse.golden lists the parsed blocks
+// and problems.
+func TestCollabCorpus(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "collab", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no corpus files found")
+	}
+
+	for _, path := range files {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc := parseCollabMarkers(filepath.Base(path), string(content))
+
+			parsed, err := yaml.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, path+".parse.golden", string(parsed))
+
+			// Clean leaves files with problems alone
+			cleaned := doc.content
+			if len(doc.Problems) == 0 {
+				cleaned = removeCollabBlocks(doc.content, completedBlocks(doc, defaultCollabAgents))
+			}
+			checkGolden(t, path+".golden", cleaned)
+		})
+	}
+}
+
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run 'go test -run TestCollabCorpus -update' to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs:\n%s", path, unifiedDiff(path, "got", string(want), got))
+	}
+}
+
+func TestRemoveCollabBlocksKeepsOpenWork(t *testing.T) {
+	content := strings.Join([]string{
+		"# Plan",
+		"",
+		"::LARS:1::",
+		"Finished",
+		"::",
+		"",
+		"::GEMINI:2::",
+		"Still open",
+		"::",
+		"",
+		"::DONE:1::",
+		"",
+	}, "\n")
+	doc := parseCollabMarkers("plan.md", content)
+	got := removeCollabBlocks(content, completedBlocks(doc, defaultCollabAgents))
+
+	want := "# Plan\n\n::GEMINI:2::\nStill open\n::\n"
+	if got != want {
+		t.Errorf("cleaned content:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestParseCollabFilesWalksEveryFolder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"plan.md", "testdata/examples.md", "docs/ai-dsl.md", "notes.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("::LARS:1::\nWork\n::\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, doc := range parseCollabFiles([]string{dir}) {
+		rel, _ := filepath.Rel(dir, doc.Path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	if strings.Join(got, ",") != "plan.md,testdata/examples.md" {
+		t.Errorf("parsed %v, want plan.md and testdata/examples.md", got)
+	}
+}
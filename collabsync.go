@@ -0,0 +1,477 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CollabLink ties a task to the ::AGENT:ID:: marker it was synced from. File
+// is the absolute path of the markdown file; links written by earlier
+// versions hold the path as given to 'dppm collab sync' and are updated by
+// the next sync run from the same folder.
+type CollabLink struct {
+	File  string `yaml:"file" json:"file"`
+	Agent string `yaml:"agent" json:"agent"`
+	ID    string `yaml:"id" json:"id"`
+}
+
+var collabSyncCmd = &cobra.Command{
+	Use:   "sync [path...]",
+	Short: "Create and update DPPM tasks from collaboration markers",
+	Long: `Sync Collaboration Markers With DPPM Tasks
+
+Bridges the DSL markers in markdown files and the tasks of a project:
+
+  • Every ::AGENT:ID:: block gets a task in the given phase. The task is
+    linked to the marker, so later syncs update it instead of creating
+    another one.
+  • Title is the first line of the block (or its "Task:" line), the
+    description is the whole body and the assignee is the agent.
+  • The IDs on the "Prerequisites:" line become task dependencies on the
+    tasks linked to those markers. Dependencies are only ever added.
+  • A marker listed in a ::DONE:: marker sets its task to done, recorded
+    as a forced change where the project workflow would not allow it.
+  • A linked task that is done while its marker is not gets a
+    ::DONE:ID:: marker appended to the markdown file. 'dppm task update
+    --status done' already does this; sync catches the tasks it missed.
+
+Files whose markers have problems (see 'dppm collab find') are never
+written to, the same as with 'dppm collab clean'.
+
+Blocks that are already DONE when first seen do not get a task. The link
+records the absolute path of the file, so a file that moves (or a checkout
+at another path) is synced as a new file.
+
+Examples:
+  dppm collab sync --project web-app --phase P1
+  dppm collab sync docs/ --project web-app --phase P2 --dry-run`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		warnAboutConflicts(projectID)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		searchPaths := []string{"."}
+		if len(args) > 0 {
+			searchPaths = args
+		}
+		projectID, _ := cmd.Flags().GetString("project")
+		phaseID, _ := cmd.Flags().GetString("phase")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		exists, err := store.ProjectExists(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking project existence: %v\n", err)
+			os.Exit(1)
+		}
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' does not exist\n", projectID)
+			os.Exit(1)
+		}
+		phase, err := store.LoadPhase(projectID, phaseID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Phase '%s' does not exist in project '%s'\n", phaseID, projectID)
+			os.Exit(1)
+		}
+		if phase.Status == "completed" || phase.Status == "cancelled" {
+			fmt.Fprintf(os.Stderr, "Error: Phase '%s' is %s; sync into an open phase\n", phaseID, phase.Status)
+			os.Exit(1)
+		}
+
+		agents, err := collabAgents(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		syncer, err := newCollabSync(projectID, phaseID, agents, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			unlock()
+			os.Exit(1)
+		}
+		if err := syncer.run(searchPaths); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			unlock()
+			os.Exit(1)
+		}
+
+		if syncer.changes > 0 && !dryRun {
+			fmt.Printf("\n🎯 HØJRE HEGN - NEXT ACTIONS:\n")
+			fmt.Printf("  dppm status project %s              # See the synced tasks\n", projectID)
+			fmt.Printf("  dppm collab find %s   # Review the markers\n", strings.Join(searchPaths, " "))
+		}
+	},
+}
+
+// collabSync holds the state of one 'dppm collab sync' run. The caller holds
+// the project lock.
+type collabSync struct {
+	projectID string
+	phaseID   string
+	agents    []string
+	dryRun    bool
+
+	all     []Task           // every task, for picking free IDs
+	byID    map[string]*Task // tasks as changed so far
+	linked  map[string]string
+	created map[string]bool
+	dirty   map[string]bool
+	changes int
+}
+
+func newCollabSync(projectID, phaseID string, agents []string, dryRun bool) (*collabSync, error) {
+	tasks, err := store.ListTasks(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &collabSync{
+		projectID: projectID,
+		phaseID:   phaseID,
+		agents:    agents,
+		dryRun:    dryRun,
+		all:       tasks,
+		byID:      make(map[string]*Task),
+		linked:    make(map[string]string),
+		created:   make(map[string]bool),
+		dirty:     make(map[string]bool),
+	}
+	for i := range tasks {
+		task := tasks[i]
+		s.byID[task.ID] = &task
+		if task.Collab != nil {
+			s.linked[collabKey(task.Collab.File, task.Collab.ID)] = task.ID
+		}
+	}
+	return s, nil
+}
+
+// collabKey identifies a marker: IDs are unique within a markdown file
+func collabKey(file, id string) string {
+	return file + "#" + id
+}
+
+func (s *collabSync) run(searchPaths []string) error {
+	if s.dryRun {
+		fmt.Println("🔄 Syncing collaboration markers (dry run, nothing is written)...")
+	} else {
+		fmt.Println("🔄 Syncing collaboration markers...")
+	}
+	fmt.Println("==========================================")
+	fmt.Printf("Project: %s, phase: %s\n", s.projectID, s.phaseID)
+	fmt.Println("Path(s):", strings.Join(searchPaths, ", "))
+	fmt.Println()
+
+	for _, doc := range parseCollabFiles(searchPaths) {
+		if err := s.syncDoc(doc); err != nil {
+			return err
+		}
+	}
+
+	if !s.dryRun {
+		for _, task := range s.all {
+			if !s.dirty[task.ID] {
+				continue
+			}
+			changed := s.byID[task.ID]
+			changed.Updated = nowTimestamp()
+			if err := store.SaveTask(changed); err != nil {
+				return fmt.Errorf("failed to save task '%s': %v", changed.ID, err)
+			}
+		}
+	}
+
+	fmt.Println("==========================================")
+	if s.changes == 0 {
+		fmt.Println("✅ Everything is in sync")
+	} else if s.dryRun {
+		fmt.Printf("✅ %d changes would be made\n", s.changes)
+	} else {
+		fmt.Printf("✅ %d changes made\n", s.changes)
+	}
+	return nil
+}
+
+// syncDoc links the blocks of one file to tasks and carries completion over
+// in both directions
+func (s *collabSync) syncDoc(doc CollabDoc) error {
+	absPath, err := filepath.Abs(doc.Path)
+	if err != nil {
+		return err
+	}
+	file := filepath.ToSlash(absPath)
+	done := doc.doneIDs()
+
+	var blocks []CollabBlock
+	for _, block := range doc.Blocks {
+		if block.IsDone() || !containsString(s.agents, block.Agent) {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	fmt.Printf("📄 %s\n", file)
+
+	// Link every block first, so prerequisites can point at later blocks
+	for _, block := range blocks {
+		if block.Unterminated {
+			fmt.Printf("   ⚠️  ::%s:%s:: (line %d) is never closed with ::, skipped\n", block.Agent, block.ID, block.StartLine)
+			continue
+		}
+		key := collabKey(file, block.ID)
+		if _, ok := s.linked[key]; ok {
+			continue
+		}
+		if s.relinkLegacy(doc.Path, file, block) || done[block.ID] {
+			continue
+		}
+		taskID, err := nextTaskID(s.all, s.phaseID, "")
+		if err != nil {
+			return fmt.Errorf("cannot pick a task ID: %v", err)
+		}
+		task := &Task{
+			ID:            taskID,
+			ProjectID:     s.projectID,
+			PhaseID:       s.phaseID,
+			Status:        "todo",
+			Priority:      "medium",
+			Reporter:      currentAuthor(),
+			Created:       nowTimestamp(),
+			Components:    []Component{},
+			Issues:        []Issue{},
+			DependencyIDs: []string{},
+			BlockedBy:     []string{},
+			Blocking:      []string{},
+			Labels:        []string{},
+			Comments:      []Comment{},
+			Collab:        &CollabLink{File: file, Agent: block.Agent, ID: block.ID},
+		}
+		s.all = append(s.all, *task)
+		s.byID[taskID] = task
+		s.linked[key] = taskID
+		s.created[taskID] = true
+		s.dirty[taskID] = true
+	}
+
+	var markDone, doneTasks []string
+	for _, block := range blocks {
+		taskID, ok := s.linked[collabKey(file, block.ID)]
+		if !ok || block.Unterminated {
+			continue
+		}
+		task := s.byID[taskID]
+		marker := fmt.Sprintf("::%s:%s::", block.Agent, block.ID)
+
+		changed := s.applyBlock(task, block)
+		changed = append(changed, s.applyPrerequisites(task, block, file, done, marker)...)
+
+		if done[block.ID] && task.Status != "done" {
+			if err := changeTaskStatus(task, "done", true); err != nil {
+				fmt.Printf("   ⚠️  %s is DONE but %s cannot be set to done: %v\n", marker, task.ID, err)
+			} else {
+				changed = append(changed, "status done")
+			}
+		} else if !done[block.ID] && task.Status == "done" {
+			markDone = append(markDone, block.ID)
+			doneTasks = append(doneTasks, task.ID)
+		}
+
+		switch {
+		case s.created[task.ID]:
+			fmt.Printf("   ➕ %s → %s %s\n", marker, task.ID, task.Title)
+			s.changes++
+		case len(changed) > 0:
+			fmt.Printf("   🔄 %s → %s updated: %s\n", marker, task.ID, strings.Join(changed, ", "))
+			s.dirty[task.ID] = true
+			s.changes++
+		}
+	}
+
+	if len(markDone) > 0 && len(doc.Problems) > 0 {
+		fmt.Printf("   ⚠️  Not marking %s done: fix the marker problems in this file first (see 'dppm collab find')\n", strings.Join(markDone, ","))
+	} else if len(markDone) > 0 {
+		fmt.Printf("   ✅ Marking %s done in the file (tasks %s)\n", strings.Join(markDone, ","), strings.Join(doneTasks, ", "))
+		s.changes++
+		if !s.dryRun {
+			if err := appendDoneMarker(doc, markDone, doneTasks); err != nil {
+				fmt.Printf("   ❌ Error writing file: %v\n", err)
+			}
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// relinkLegacy moves a link that holds the file path as given to an earlier
+// sync over to the absolute path of the file
+func (s *collabSync) relinkLegacy(path, file string, block CollabBlock) bool {
+	legacyKey := collabKey(filepath.ToSlash(filepath.Clean(path)), block.ID)
+	taskID, ok := s.linked[legacyKey]
+	if !ok {
+		return false
+	}
+	task := s.byID[taskID]
+	task.Collab.File = file
+	delete(s.linked, legacyKey)
+	s.linked[collabKey(file, block.ID)] = taskID
+	s.dirty[taskID] = true
+	s.changes++
+	fmt.Printf("   🔗 ::%s:%s:: → %s now linked by absolute path\n", block.Agent, block.ID, taskID)
+	return true
+}
+
+// applyBlock copies title, description and assignee from a block to its task
+// and returns the names of the fields that changed
+func (s *collabSync) applyBlock(task *Task, block CollabBlock) []string {
+	title := truncate(block.Summary(), 100)
+	if title == "" {
+		title = fmt.Sprintf("%s task %s", block.Agent, block.ID)
+	}
+	description := block.Body
+	if err := ValidateDescription(description); err != nil {
+		fmt.Printf("   ⚠️  ::%s:%s:: body not used as description: %v\n", block.Agent, block.ID, err)
+		description = task.Description
+	}
+
+	if s.created[task.ID] {
+		task.Title, task.Description, task.Assignee = title, description, block.Agent
+		return nil
+	}
+
+	var changed []string
+	if task.Title != title {
+		recordChange(task, "title", task.Title, title)
+		task.Title = title
+		changed = append(changed, "title")
+	}
+	if task.Description != description {
+		recordChange(task, "description", task.Description, description)
+		task.Description = description
+		changed = append(changed, "description")
+	}
+	if task.Assignee != block.Agent {
+		recordChange(task, "assignee", task.Assignee, block.Agent)
+		task.Assignee = block.Agent
+		changed = append(changed, "assignee")
+	}
+	return changed
+}
+
+// applyPrerequisites adds a dependency on the task of every prerequisite
+// marker. Prerequisites that are DONE without a task are simply satisfied.
+func (s *collabSync) applyPrerequisites(task *Task, block CollabBlock, file string, done map[string]bool, marker string) []string {
+	var changed []string
+	for _, prerequisite := range block.Prerequisites {
+		dependsOnID, ok := s.linked[collabKey(file, prerequisite)]
+		if !ok {
+			if !done[prerequisite] {
+				fmt.Printf("   ⚠️  %s: prerequisite %s is not a marker in this file\n", marker, prerequisite)
+			}
+			continue
+		}
+		if dependsOnID == task.ID || containsString(task.DependencyIDs, dependsOnID) {
+			continue
+		}
+
+		graph := make(map[string]Task, len(s.byID))
+		for id, t := range s.byID {
+			graph[id] = *t
+		}
+		if path := findDependencyPath(graph, dependsOnID, task.ID); path != nil {
+			fmt.Printf("   ⚠️  %s: depending on %s would create a cycle: %s → %s\n", marker, dependsOnID, task.ID, strings.Join(path, " → "))
+			continue
+		}
+
+		before := strings.Join(task.DependencyIDs, ", ")
+		task.DependencyIDs = append(task.DependencyIDs, dependsOnID)
+		task.BlockedBy = addString(task.BlockedBy, dependsOnID)
+		if !s.created[task.ID] {
+			recordChange(task, "dependency_ids", before, strings.Join(task.DependencyIDs, ", "))
+		}
+		changed = addString(changed, "dependencies")
+
+		dependency := s.byID[dependsOnID]
+		dependency.Blocking = addString(dependency.Blocking, task.ID)
+		s.dirty[dependsOnID] = true
+	}
+	return changed
+}
+
+// markCollabDone appends a ::DONE:: marker for the block a task is linked to,
+// once the task is done. Only the file the link records is touched; a file
+// that cannot be read here is left for the next 'dppm collab sync'.
+func markCollabDone(task *Task) {
+	link := task.Collab
+	if link == nil || task.Status != "done" {
+		return
+	}
+	path := filepath.FromSlash(link.File)
+	if !filepath.IsAbs(path) {
+		fmt.Printf("⚠️  ::%s:%s:: not marked done: the link predates absolute paths ('dppm collab sync' will mark it)\n", link.Agent, link.ID)
+		return
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Printf("⚠️  ::%s:%s:: not marked done: %v ('dppm collab sync' will mark it)\n", link.Agent, link.ID, err)
+		return
+	}
+	doc := parseCollabMarkers(path, string(content))
+	if doc.doneIDs()[link.ID] {
+		return
+	}
+	found := false
+	for _, block := range doc.Blocks {
+		if block.Agent == link.Agent && block.ID == link.ID && !block.Unterminated {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	if err := appendDoneMarker(doc, []string{link.ID}, []string{task.ID}); err != nil {
+		fmt.Printf("⚠️  ::%s:%s:: not marked done: %v\n", link.Agent, link.ID, err)
+		return
+	}
+	fmt.Printf("✅ Marked ::%s:%s:: done in %s\n", link.Agent, link.ID, link.File)
+}
+
+// appendDoneMarker adds a ::DONE:: marker for ids at the end of the file.
+// Files with marker problems are refused, as 'dppm collab clean' does.
+func appendDoneMarker(doc CollabDoc, ids, taskIDs []string) error {
+	if len(doc.Problems) > 0 {
+		return fmt.Errorf("%s has marker problems; fix them first (see 'dppm collab find')", doc.Path)
+	}
+	info, err := os.Stat(doc.Path)
+	if err != nil {
+		return err
+	}
+	content := doc.content
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += fmt.Sprintf("\n::%s:%s:: Completed in DPPM (%s) ::\n", doneMarker, strings.Join(ids, ","), strings.Join(taskIDs, ", "))
+	return replaceFile(doc.Path, []byte(content), info.Mode())
+}
+
+func init() {
+	collabSyncCmd.Flags().StringP("project", "p", "", "Project to sync the markers into (required)")
+	collabSyncCmd.Flags().String("phase", "", "Phase new tasks are created in (required)")
+	collabSyncCmd.Flags().Bool("dry-run", false, "Show what would change without writing anything")
+	collabSyncCmd.MarkFlagRequired("project")
+	collabSyncCmd.MarkFlagRequired("phase")
+}
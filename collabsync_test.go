@@ -0,0 +1,228 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const syncTestPlan = `# Plan
+
+::LARS:1::
+Build the API
+::
+
+::GEMINI:2::
+Write the client
+Prerequisites: 1
+::
+
+::LARS:3::
+Already finished
+::
+
+::DONE:3::
+`
+
+// runCollabSync syncs dir into phase P1 of project demo
+func runCollabSync(t *testing.T, dir string) *collabSync {
+	t.Helper()
+	s, err := newCollabSync("demo", "P1", defaultCollabAgents, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.run([]string{dir}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// linkedTasks maps marker IDs to the tasks linked to them
+func linkedTasks(t *testing.T) map[string]Task {
+	t.Helper()
+	tasks, err := store.ListTasks("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	linked := make(map[string]Task)
+	for _, task := range tasks {
+		if task.Collab != nil {
+			linked[task.Collab.ID] = task
+		}
+	}
+	return linked
+}
+
+func newCollabSyncTest(t *testing.T) string {
+	t.Helper()
+	store = NewMemoryStore()
+	if err := store.SaveProject(&Project{ID: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SavePhase(&Phase{ID: "P1", ProjectID: "demo", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveTask(&Task{ID: "T1.1", ProjectID: "demo", PhaseID: "P1", Title: "Existing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte(syncTestPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCollabSyncLinksMarkers(t *testing.T) {
+	dir := newCollabSyncTest(t)
+	runCollabSync(t, dir)
+
+	linked := linkedTasks(t)
+	if len(linked) != 2 {
+		t.Fatalf("linked markers %v, want 1 and 2 (3 was DONE when first seen)", linked)
+	}
+	api, client := linked["1"], linked["2"]
+	if api.ID != "T1.2" || api.Title != "Build the API" || api.Assignee != "LARS" || api.Status != "todo" {
+		t.Errorf("task for ::LARS:1:: = %s %q assigned to %q (%s)", api.ID, api.Title, api.Assignee, api.Status)
+	}
+	if client.ID != "T1.3" || client.Collab.Agent != "GEMINI" || client.Collab.File != filepath.ToSlash(filepath.Join(dir, "plan.md")) {
+		t.Errorf("task for ::GEMINI:2:: = %s linked to %+v", client.ID, client.Collab)
+	}
+
+	// The prerequisite becomes a dependency in both directions
+	if !reflect.DeepEqual(client.DependencyIDs, []string{"T1.2"}) || !reflect.DeepEqual(client.BlockedBy, []string{"T1.2"}) {
+		t.Errorf("T1.3 depends on %v, blocked by %v", client.DependencyIDs, client.BlockedBy)
+	}
+	if !reflect.DeepEqual(api.Blocking, []string{"T1.3"}) {
+		t.Errorf("T1.2 blocking %v", api.Blocking)
+	}
+
+	// A second run finds nothing to do
+	if s := runCollabSync(t, dir); s.changes != 0 {
+		t.Errorf("second sync made %d changes", s.changes)
+	}
+	if got := linkedTasks(t); len(got) != 2 || got["1"].ID != "T1.2" {
+		t.Errorf("second sync relinked the markers: %v", got)
+	}
+}
+
+func TestCollabSyncCarriesCompletionBothWays(t *testing.T) {
+	dir := newCollabSyncTest(t)
+	path := filepath.Join(dir, "plan.md")
+	runCollabSync(t, dir)
+
+	// A task done in DPPM gets a DONE marker in the file
+	api := linkedTasks(t)["1"]
+	api.Status = "done"
+	if err := store.SaveTask(&api); err != nil {
+		t.Fatal(err)
+	}
+	runCollabSync(t, dir)
+	content, _ := os.ReadFile(path)
+	if !strings.HasSuffix(string(content), "\n::DONE:1:: Completed in DPPM (T1.2) ::\n") {
+		t.Errorf("file does not end with a DONE marker for 1:\n%s", content)
+	}
+
+	// A DONE marker in the file completes the task
+	if err := os.WriteFile(path, append(content, []byte("\n::DONE:2::\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runCollabSync(t, dir)
+	client := linkedTasks(t)["2"]
+	if client.Status != "done" {
+		t.Fatalf("task for ::GEMINI:2:: is %s after its DONE marker", client.Status)
+	}
+	if last := client.History[len(client.History)-1]; last.Field != "status" || !last.Forced {
+		t.Errorf("completion recorded as %+v, want a forced status change", last)
+	}
+}
+
+func TestCollabSyncDryRunWritesNothing(t *testing.T) {
+	dir := newCollabSyncTest(t)
+	s, err := newCollabSync("demo", "P1", defaultCollabAgents, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.run([]string{dir}); err != nil {
+		t.Fatal(err)
+	}
+	if s.changes != 2 {
+		t.Errorf("dry run counted %d changes, want 2", s.changes)
+	}
+	if linked := linkedTasks(t); len(linked) != 0 {
+		t.Errorf("dry run created tasks %v", linked)
+	}
+}
+
+// chdir changes the working directory for the rest of the test
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestMarkCollabDoneWritesOnlyTheLinkedFile(t *testing.T) {
+	dir := newCollabSyncTest(t)
+	chdir(t, dir)
+	runCollabSync(t, ".")
+
+	// Another checkout with the same relative path and markers
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "plan.md"), []byte(syncTestPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, other)
+
+	api := linkedTasks(t)["1"]
+	api.Status = "done"
+	markCollabDone(&api)
+
+	content, _ := os.ReadFile(filepath.Join(dir, "plan.md"))
+	if !strings.HasSuffix(string(content), "\n::DONE:1:: Completed in DPPM (T1.2) ::\n") {
+		t.Errorf("linked file does not end with a DONE marker for 1:\n%s", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(other, "plan.md")); string(content) != syncTestPlan {
+		t.Errorf("file in the working directory was changed:\n%s", content)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("files left next to plan.md: %v", entries)
+	}
+}
+
+func TestCollabSyncUpgradesRelativeLinks(t *testing.T) {
+	dir := newCollabSyncTest(t)
+	chdir(t, dir)
+	legacy := &Task{ID: "T1.2", ProjectID: "demo", PhaseID: "P1", Title: "Build the API", Assignee: "LARS", Status: "done",
+		Collab: &CollabLink{File: "plan.md", Agent: "LARS", ID: "1"}}
+	if err := store.SaveTask(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	// A relative link is not followed from wherever the command runs
+	markCollabDone(legacy)
+	if content, _ := os.ReadFile("plan.md"); string(content) != syncTestPlan {
+		t.Fatalf("relative link was followed:\n%s", content)
+	}
+
+	runCollabSync(t, ".")
+	linked := linkedTasks(t)
+	if api := linked["1"]; api.ID != "T1.2" || api.Collab.File != filepath.ToSlash(filepath.Join(dir, "plan.md")) {
+		t.Errorf("::LARS:1:: linked to %s %+v, want T1.2 by absolute path", api.ID, api.Collab)
+	}
+	if len(linked) != 2 {
+		t.Errorf("linked markers %v, want 1 and 2", linked)
+	}
+	content, _ := os.ReadFile("plan.md")
+	if !strings.HasSuffix(string(content), "\n::DONE:1:: Completed in DPPM (T1.2) ::\n") {
+		t.Errorf("file does not end with a DONE marker for 1:\n%s", content)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var validCommentTypes = []string{"note", "question", "handoff", "decision"}
+
+// latestCommentCount is how many comments 'dppm task show' prints
+const latestCommentCount = 3
+
+var taskCommentCmd = &cobra.Command{
+	Use:   "comment",
+	Short: "Comment threads on tasks",
+	Long: `Task Comments
+
+Leave notes, questions, handoffs and decisions directly on a task, so the
+next person (or AI agent) picking it up finds them with 'dppm task show'.
+
+Comment types:
+  note       General remark (default)
+  question   Something that needs an answer
+  handoff    Context for whoever continues the work
+  decision   A decision and its reasoning
+
+The author is taken from DPPM_AUTHOR, the 'author' setting in
+~/.dppm/config.yaml, or the current system user.
+
+Available Commands:
+  add       Add a comment to a task
+  list      List the comments of a task
+
+Examples:
+  dppm task comment add T1.1 "Token refresh done, UI still open" --type handoff
+  dppm task comment list T1.1 --type question`,
+}
+
+var taskCommentAddCmd = &cobra.Command{
+	Use:   "add [task-id] [message]",
+	Short: "Add a comment to a task",
+	Long: `Add a Task Comment
+
+Appends a comment to the task's comments section.
+
+Examples:
+  dppm task comment add T1.1 "Should we support refresh tokens?" --type question
+  dppm task comment add T1.1 "Use JWT, sessions do not scale" --type decision
+  DPPM_AUTHOR=gemini dppm task comment add T1.1 "API ready for UI work" --type handoff`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		message := strings.TrimSpace(args[1])
+		projectID, _ := cmd.Flags().GetString("project")
+		commentType, _ := cmd.Flags().GetString("type")
+
+		if !containsString(validCommentTypes, commentType) {
+			fmt.Fprintf(os.Stderr, "Error: Invalid comment type '%s'. Must be one of: %s\n", commentType, strings.Join(validCommentTypes, ", "))
+			os.Exit(1)
+		}
+		if message == "" {
+			fmt.Fprintln(os.Stderr, "Error: Comment message cannot be empty")
+			os.Exit(1)
+		}
+		if err := ValidateDescription(message); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid message: %v\n", err)
+			os.Exit(1)
+		}
+
+		comment := Comment{
+			Timestamp: nowTimestamp(),
+			Author:    currentAuthor(),
+			Content:   message,
+			Type:      commentType,
+		}
+		task, err := modifyTask(cmd, projectID, taskID, func(task *Task) error {
+			task.Comments = append(task.Comments, comment)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Comment (%s) added to task '%s' by %s\n", commentType, task.ID, comment.Author)
+	},
+}
+
+var taskCommentListCmd = &cobra.Command{
+	Use:   "list [task-id]",
+	Short: "List the comments of a task",
+	Long: `List Task Comments
+
+Shows the comments of a task, oldest first.
+
+Examples:
+  dppm task comment list T1.1
+  dppm task comment list T1.1 --type handoff
+  dppm task comment list T1.1 --output json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		commentType, _ := cmd.Flags().GetString("type")
+
+		task, err := loadTaskForCommand(projectID, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found\n", args[0])
+			os.Exit(1)
+		}
+
+		comments := []Comment{}
+		for _, comment := range task.Comments {
+			if commentType == "" || comment.Type == commentType {
+				comments = append(comments, comment)
+			}
+		}
+		if printStructured(comments) {
+			return
+		}
+
+		fmt.Printf("Comments: %s\n", task.ID)
+		fmt.Println("================")
+
+		if len(comments) == 0 {
+			fmt.Println("No comments.")
+			return
+		}
+		for _, comment := range comments {
+			printComment(comment)
+		}
+	},
+}
+
+func printComment(comment Comment) {
+	fmt.Printf("%s %s  %s (%s)\n", commentIcon(comment.Type), comment.Timestamp, comment.Author, comment.Type)
+	for _, line := range strings.Split(comment.Content, "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+}
+
+// printLatestComments shows the most recent comments of a task
+func printLatestComments(comments []Comment) {
+	if len(comments) == 0 {
+		return
+	}
+
+	fmt.Printf("\nLatest Comments (%d total):\n", len(comments))
+	start := len(comments) - latestCommentCount
+	if start < 0 {
+		start = 0
+	}
+	for _, comment := range comments[start:] {
+		printComment(comment)
+	}
+}
+
+func commentIcon(commentType string) string {
+	switch commentType {
+	case "question":
+		return "❓"
+	case "handoff":
+		return "🤝"
+	case "decision":
+		return "⚖️ "
+	default:
+		return "💬"
+	}
+}
+
+func init() {
+	taskCommentAddCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskCommentAddCmd.Flags().String("type", "note", "Comment type (note, question, handoff, decision)")
+
+	taskCommentListCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskCommentListCmd.Flags().String("type", "", "Only show comments of this type")
+
+	taskCommentCmd.AddCommand(taskCommentAddCmd)
+	taskCommentCmd.AddCommand(taskCommentListCmd)
+	taskCmd.AddCommand(taskCommentCmd)
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	validComponentTypes    = []string{"feature", "bug", "enhancement", "documentation", "testing"}
+	validComponentStatuses = []string{"todo", "in_progress", "done"}
+
+	componentIDRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+)
+
+var taskComponentCmd = &cobra.Command{
+	Use:   "component",
+	Short: "Manage the components of a task",
+	Long: `Task Component Management
+
+Break a task into components (parts) and track each one separately.
+Every change recalculates the task's progress section:
+  total_components, completed_components, completion_percentage
+
+Component IDs default to the task ID with a running number (T1.1.1, T1.1.2).
+
+Available Commands:
+  add       Add a component to a task
+  update    Update a component
+  list      List the components of a task
+
+Examples:
+  dppm task component add T1.1 --title "API Documentation" --type documentation
+  dppm task component update T1.1.1 --status done
+  dppm task component list T1.1`,
+}
+
+var taskComponentAddCmd = &cobra.Command{
+	Use:   "add [task-id]",
+	Short: "Add a component to a task",
+	Long: `Add a Task Component
+
+Adds a component to the task and recalculates the task's progress.
+
+Types: feature, bug, enhancement, documentation, testing
+Statuses: todo, in_progress, done
+
+Examples:
+  dppm task component add T1.1 --title "Backend API" --type feature
+  dppm task component add T1.1 --title "Unit Tests" --type testing --assigned-to gemini
+  dppm task component add T1.1 --id auth-frontend --title "Frontend UI"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		componentID, _ := cmd.Flags().GetString("id")
+		title, _ := cmd.Flags().GetString("title")
+		componentType, _ := cmd.Flags().GetString("type")
+		status, _ := cmd.Flags().GetString("status")
+		assignedTo, _ := cmd.Flags().GetString("assigned-to")
+		description, _ := cmd.Flags().GetString("description")
+
+		if err := validateComponentFields(componentType, status); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := ValidateDescription(title); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid title: %v\n", err)
+			os.Exit(1)
+		}
+		if componentID != "" && (!componentIDRegex.MatchString(componentID) || strings.Contains(componentID, "..")) {
+			fmt.Fprintf(os.Stderr, "Error: Invalid component ID '%s'\n", componentID)
+			os.Exit(1)
+		}
+
+		var added Component
+		task, err := modifyTask(cmd, projectID, taskID, func(task *Task) error {
+			id := componentID
+			if id == "" {
+				id = nextComponentID(*task)
+			}
+			if findComponent(*task, id) >= 0 {
+				return fmt.Errorf("component '%s' already exists in task '%s'", id, task.ID)
+			}
+
+			now := nowTimestamp()
+			added = Component{
+				ID:          id,
+				Title:       title,
+				Status:      status,
+				Type:        componentType,
+				Description: description,
+				AssignedTo:  assignedTo,
+				Created:     now,
+				Updated:     now,
+			}
+			task.Components = append(task.Components, added)
+			recordChange(task, "components."+id, "", title)
+			recalculateProgress(task)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Component '%s' added to task '%s'\n", added.ID, task.ID)
+		printProgress(task.Progress)
+		fmt.Printf("\n🎯 HØJRE HEGN - NEXT ACTIONS:\n")
+		fmt.Printf("  dppm task component update %s --status in_progress  # Start working on it\n", added.ID)
+		fmt.Printf("  dppm task component list %s                         # See all components\n", task.ID)
+	},
+}
+
+var taskComponentUpdateCmd = &cobra.Command{
+	Use:   "update [component-id]",
+	Short: "Update a component",
+	Long: `Update a Task Component
+
+Updates the given properties of a component and recalculates the task's
+progress. Without --task, all tasks (of --project, or of every project) are
+searched for the component ID; the ID must then be unique.
+
+Pass --if-revision N to refuse the update when the task holding the
+component has changed since you read revision N.
+
+Examples:
+  dppm task component update T1.1.1 --status done
+  dppm task component update auth-frontend --task T1.1 --assigned-to gemini
+  dppm task component update T1.1.1 --status done --if-revision 5`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		componentID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		taskID, _ := cmd.Flags().GetString("task")
+		componentType, _ := cmd.Flags().GetString("type")
+		status, _ := cmd.Flags().GetString("status")
+
+		if err := validateComponentFields(componentType, status); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if taskID == "" {
+			owner, err := findComponentOwner(projectID, componentID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			projectID, taskID = owner.ProjectID, owner.ID
+		}
+
+		task, err := modifyTask(cmd, projectID, taskID, func(task *Task) error {
+			index := findComponent(*task, componentID)
+			if index < 0 {
+				return fmt.Errorf("component '%s' not found in task '%s'", componentID, task.ID)
+			}
+			component := &task.Components[index]
+			field := "components." + component.ID
+
+			if cmd.Flags().Changed("status") {
+				recordChange(task, field+".status", component.Status, status)
+				component.Status = status
+			}
+			if cmd.Flags().Changed("type") {
+				recordChange(task, field+".type", component.Type, componentType)
+				component.Type = componentType
+			}
+			if cmd.Flags().Changed("title") {
+				title, _ := cmd.Flags().GetString("title")
+				recordChange(task, field+".title", component.Title, title)
+				component.Title = title
+			}
+			if cmd.Flags().Changed("assigned-to") {
+				assignedTo, _ := cmd.Flags().GetString("assigned-to")
+				recordChange(task, field+".assigned_to", component.AssignedTo, assignedTo)
+				component.AssignedTo = assignedTo
+			}
+			if cmd.Flags().Changed("description") {
+				description, _ := cmd.Flags().GetString("description")
+				recordChange(task, field+".description", component.Description, description)
+				component.Description = description
+			}
+			component.Updated = nowTimestamp()
+
+			recalculateProgress(task)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Component '%s' of task '%s' updated\n", componentID, task.ID)
+		printProgress(task.Progress)
+	},
+}
+
+var taskComponentListCmd = &cobra.Command{
+	Use:   "list [task-id]",
+	Short: "List the components of a task",
+	Long: `List Task Components
+
+Shows every component of a task with its status, type and assignee,
+followed by the task's component progress.
+
+Examples:
+  dppm task component list T1.1
+  dppm task component list T1.1 --project web-app --output json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+
+		task, err := loadTaskForCommand(projectID, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found\n", args[0])
+			os.Exit(1)
+		}
+
+		components := task.Components
+		if components == nil {
+			components = []Component{}
+		}
+		if printStructured(ComponentList{TaskID: task.ID, Components: components, Progress: task.Progress}) {
+			return
+		}
+
+		fmt.Printf("Components: %s\n", task.ID)
+		fmt.Println("================")
+
+		if len(task.Components) == 0 {
+			fmt.Println("No components.")
+			return
+		}
+
+		for _, component := range task.Components {
+			fmt.Printf("%s %s - %s [%s]", componentStatusIcon(component.Status), component.ID, component.Title, component.Type)
+			if component.AssignedTo != "" {
+				fmt.Printf(" → %s", component.AssignedTo)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+		printProgress(task.Progress)
+	},
+}
+
+// ComponentList is the machine-readable form of 'dppm task component list'
+type ComponentList struct {
+	TaskID     string      `yaml:"task_id" json:"task_id"`
+	Components []Component `yaml:"components" json:"components"`
+	Progress   Progress    `yaml:"progress" json:"progress"`
+}
+
+// recalculateProgress derives the task's progress section from its components
+// and issues, so it is never stale after a change
+func recalculateProgress(task *Task) {
+	progress := Progress{}
+
+	for _, component := range task.Components {
+		progress.TotalComponents++
+		if component.Status == "done" {
+			progress.CompletedComponents++
+		}
+	}
+	if progress.TotalComponents > 0 {
+		progress.CompletionPercentage = progress.CompletedComponents * 100 / progress.TotalComponents
+	}
+
+	for _, issue := range task.Issues {
+		progress.TotalIssues++
+		if isIssueResolved(issue) {
+			progress.ResolvedIssues++
+		} else if issue.Type == "bug" {
+			progress.OpenBugs++
+		}
+	}
+
+	task.Progress = progress
+}
+
+func printProgress(progress Progress) {
+	if progress.TotalComponents > 0 {
+		fmt.Printf("📊 Progress: %d/%d components done (%d%%)\n", progress.CompletedComponents, progress.TotalComponents, progress.CompletionPercentage)
+	}
+}
+
+func validateComponentFields(componentType, status string) error {
+	if componentType != "" && !containsString(validComponentTypes, componentType) {
+		return fmt.Errorf("invalid component type '%s'. Must be one of: %s", componentType, strings.Join(validComponentTypes, ", "))
+	}
+	if status != "" && !containsString(validComponentStatuses, status) {
+		return fmt.Errorf("invalid component status '%s'. Must be one of: %s", status, strings.Join(validComponentStatuses, ", "))
+	}
+	return nil
+}
+
+// nextComponentID numbers components after their task: T1.1.1, T1.1.2, ...
+func nextComponentID(task Task) string {
+	highest := 0
+	prefix := task.ID + "."
+	for _, component := range task.Components {
+		if n, err := strconv.Atoi(strings.TrimPrefix(component.ID, prefix)); err == nil && strings.HasPrefix(component.ID, prefix) && n > highest {
+			highest = n
+		}
+	}
+	return fmt.Sprintf("%s%d", prefix, highest+1)
+}
+
+func findComponent(task Task, componentID string) int {
+	for i, component := range task.Components {
+		if component.ID == componentID {
+			return i
+		}
+	}
+	return -1
+}
+
+// findComponentOwner locates the single task that has the component
+func findComponentOwner(projectID, componentID string) (*Task, error) {
+	projectIDs := []string{projectID}
+	if projectID == "" {
+		var err error
+		if projectIDs, err = allProjectIDs(); err != nil {
+			return nil, err
+		}
+	}
+
+	var owners []Task
+	for _, id := range projectIDs {
+		tasks, err := loadProjectTasks(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			if findComponent(task, componentID) >= 0 {
+				owners = append(owners, task)
+			}
+		}
+	}
+
+	switch len(owners) {
+	case 0:
+		return nil, fmt.Errorf("component '%s' not found", componentID)
+	case 1:
+		return &owners[0], nil
+	default:
+		var names []string
+		for _, task := range owners {
+			names = append(names, task.ProjectID+"/"+task.ID)
+		}
+		return nil, fmt.Errorf("component '%s' exists in several tasks (%s), use --task", componentID, strings.Join(names, ", "))
+	}
+}
+
+func componentStatusIcon(status string) string {
+	switch status {
+	case "done":
+		return "✅"
+	case "in_progress":
+		return "🔄"
+	default:
+		return "📋"
+	}
+}
+
+func init() {
+	taskComponentAddCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskComponentAddCmd.Flags().String("id", "", "Component ID (default: next T1.1.N)")
+	taskComponentAddCmd.Flags().StringP("title", "t", "", "Component title (required)")
+	taskComponentAddCmd.Flags().String("type", "feature", "Component type (feature, bug, enhancement, documentation, testing)")
+	taskComponentAddCmd.Flags().String("status", "todo", "Component status (todo, in_progress, done)")
+	taskComponentAddCmd.Flags().String("assigned-to", "", "Component assignee")
+	taskComponentAddCmd.Flags().StringP("description", "d", "", "Component description")
+	taskComponentAddCmd.MarkFlagRequired("title")
+
+	taskComponentUpdateCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskComponentUpdateCmd.Flags().String("task", "", "Task the component belongs to (if not specified, searches all tasks)")
+	taskComponentUpdateCmd.Flags().StringP("title", "t", "", "Component title")
+	taskComponentUpdateCmd.Flags().String("type", "", "Component type (feature, bug, enhancement, documentation, testing)")
+	taskComponentUpdateCmd.Flags().String("status", "", "Component status (todo, in_progress, done)")
+	taskComponentUpdateCmd.Flags().String("assigned-to", "", "Component assignee")
+	taskComponentUpdateCmd.Flags().StringP("description", "d", "", "Component description")
+	taskComponentUpdateCmd.Flags().Int("if-revision", 0, "Only update if the task is still at this revision")
+
+	taskComponentListCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+
+	taskComponentCmd.AddCommand(taskComponentAddCmd)
+	taskComponentCmd.AddCommand(taskComponentUpdateCmd)
+	taskComponentCmd.AddCommand(taskComponentListCmd)
+	taskCmd.AddCommand(taskComponentCmd)
+}
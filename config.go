@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// UserConfig holds per-user settings from ~/.dppm/config.yaml
+type UserConfig struct {
+	// Author is used for history, comments and time logs unless DPPM_AUTHOR is set
+	Author string `yaml:"author,omitempty"`
+
+	// Collab sets the collaboration agent tags for projects without their own
+	Collab CollabConfig `yaml:"collab,omitempty"`
+}
+
+var (
+	userConfig     UserConfig
+	userConfigOnce sync.Once
+)
+
+// getUserConfigPath returns the path of the user config file in ~/.dppm
+func getUserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".dppm", "config.yaml"), nil
+}
+
+// loadUserConfig reads ~/.dppm/config.yaml once per run. A missing file is an
+// empty config; a broken one is reported and ignored.
+func loadUserConfig() UserConfig {
+	userConfigOnce.Do(func() {
+		path, err := getUserConfigPath()
+		if err != nil {
+			return
+		}
+		if err := readYAMLFile(path, &userConfig); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "⚠️  Ignoring %s: %v\n", path, err)
+			userConfig = UserConfig{}
+		}
+	})
+	return userConfig
+}
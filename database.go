@@ -1,5 +1,10 @@
 package main
 
+import (
+	"sort"
+	"strings"
+)
+
 // Database functionality temporarily disabled to avoid CGO dependency
 // Uncomment and refactor if ERD features are needed in the future
 
@@ -16,15 +21,78 @@ func closeDatabase() error {
 	return nil
 }
 
-// Stub implementations for database-related functions used in task.go
-func getAIGuidance() ([]map[string]string, error) {
-	// Return empty guidance - database disabled
-	return []map[string]string{}, nil
+// getAIGuidance returns ready and blocked todo tasks of a project, computed
+// from the YAML files like the former ai_guidance view: ready tasks first,
+// each group ordered by task ID
+func getAIGuidance(projectID string) ([]map[string]string, error) {
+	tasks, err := store.ListTasks(projectID)
+	if err != nil {
+		return nil, err
+	}
+	status := buildProjectStatus(projectID, tasks)
+
+	sort.Slice(status.Ready, func(i, j int) bool { return lessTaskID(status.Ready[i].ID, status.Ready[j].ID) })
+	sort.Slice(status.Blocked, func(i, j int) bool { return lessTaskID(status.Blocked[i].ID, status.Blocked[j].ID) })
+
+	guidance := []map[string]string{}
+	for _, ready := range status.Ready {
+		guidance = append(guidance, map[string]string{
+			"type":    "READY",
+			"task_id": ready.ID,
+			"title":   ready.Title,
+			"message": "✅ Can start immediately",
+			"command": "dppm task update " + ready.ID + " --status in_progress",
+		})
+	}
+	for _, blocked := range status.Blocked {
+		guidance = append(guidance, map[string]string{
+			"type":    "BLOCKED",
+			"task_id": blocked.ID,
+			"title":   blocked.Title,
+			"message": "🚫 Waiting for: " + strings.Join(refTitles(blocked.BlockedBy), ", "),
+			"command": "Complete dependencies first",
+		})
+	}
+	return guidance, nil
 }
 
-func getSuggestedTaskIDs() ([]string, error) {
-	// Return empty suggestions - database disabled
-	return []string{}, nil
+// getSuggestedTaskIDs returns the next free task ID of every open phase of a
+// project, like the former next_available_tasks view
+func getSuggestedTaskIDs(projectID string) ([]string, error) {
+	phases, err := store.ListPhases(projectID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := store.ListTasks(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(phases, func(i, j int) bool {
+		a, _ := phaseNumber(phases[i].ID)
+		b, _ := phaseNumber(phases[j].ID)
+		if a != b {
+			return a < b
+		}
+		return phases[i].ID < phases[j].ID
+	})
+
+	var suggestions []string
+	for _, phase := range phases {
+		if phase.Status == "completed" || phase.Status == "cancelled" {
+			continue
+		}
+		if nextID, err := nextTaskID(tasks, phase.ID, ""); err == nil {
+			suggestions = append(suggestions, nextID)
+		}
+	}
+
+	// If no phases exist, suggest creating first phase and task. When every
+	// phase is closed there is nothing to suggest: T1.1 may well be taken.
+	if len(phases) == 0 {
+		return []string{"T1.1"}, nil
+	}
+	return suggestions, nil
 }
 
 // Original database code preserved below for future reference
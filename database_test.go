@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetSuggestedTaskIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		phases []Phase
+		tasks  []Task
+		want   []string
+	}{
+		{
+			name: "no phases yet",
+			want: []string{"T1.1"},
+		},
+		{
+			name:   "every phase closed",
+			phases: []Phase{{ID: "P1", Status: "completed"}, {ID: "P2", Status: "cancelled"}},
+			tasks:  []Task{{ID: "T1.1", PhaseID: "P1"}},
+			want:   nil,
+		},
+		{
+			name:   "open phases in numeric order",
+			phases: []Phase{{ID: "P10"}, {ID: "P2", Status: "active"}, {ID: "P1", Status: "completed"}},
+			tasks:  []Task{{ID: "T1.1", PhaseID: "P1"}, {ID: "T2.1", PhaseID: "P2"}, {ID: "T2.9", PhaseID: "P2"}},
+			want:   []string{"T2.10", "T10.1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store = NewMemoryStore()
+			for _, phase := range test.phases {
+				phase := phase
+				phase.ProjectID = "demo"
+				if err := store.SavePhase(&phase); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, task := range test.tasks {
+				task := task
+				task.ProjectID = "demo"
+				if err := store.SaveTask(&task); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := getSuggestedTaskIDs("demo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("suggestions %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetAIGuidanceOrdersByTaskNumber(t *testing.T) {
+	store = NewMemoryStore()
+	for _, task := range []Task{
+		{ID: "T1.10", Status: "todo"},
+		{ID: "T1.2", Status: "todo"},
+		{ID: "T2.1", Status: "todo"},
+		{ID: "T1.9", Status: "todo", DependencyIDs: []string{"T1.2"}},
+		{ID: "T1.11", Status: "todo", DependencyIDs: []string{"T1.2"}},
+	} {
+		task := task
+		task.ProjectID = "demo"
+		if err := store.SaveTask(&task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	guidance, err := getAIGuidance("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, guide := range guidance {
+		got = append(got, guide["type"]+" "+guide["task_id"])
+	}
+	want := []string{"READY T1.2", "READY T1.10", "READY T2.1", "BLOCKED T1.9", "BLOCKED T1.11"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("guidance %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var taskDependencyCmd = &cobra.Command{
+	Use:   "dependency",
+	Short: "Manage task dependencies",
+	Long: `Task Dependency Management
+
+Add, remove and inspect dependencies between tasks in the same project.
+Dependencies may cross phases (T2.1 can depend on T1.3).
+
+Every change keeps the related fields consistent on both tasks:
+  dependency_ids / blocked_by   Tasks this task waits for
+  blocking                      Tasks waiting for this task
+
+Dependencies that would create a cycle are rejected.
+
+Available Commands:
+  add       Make a task depend on another task
+  remove    Remove a dependency
+  check     Show what a task waits for and what waits for it
+
+Examples:
+  dppm task dependency add T1.2 --depends-on T1.1
+  dppm task dependency remove T1.2 T1.1
+  dppm task dependency check T1.2`,
+}
+
+var taskDependencyAddCmd = &cobra.Command{
+	Use:   "add [task-id]",
+	Short: "Make a task depend on another task",
+	Long: `Add a Task Dependency
+
+Makes task-id depend on the task given with --depends-on. Both tasks must
+exist in the same project. The dependency is rejected if it would create a
+cycle (e.g. T1.1 → T1.2 → T1.1).
+
+Examples:
+  dppm task dependency add T1.2 --depends-on T1.1
+  dppm task dependency add T2.1 --depends-on T1.3 --project web-app`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		dependsOn, _ := cmd.Flags().GetString("depends-on")
+		projectID, _ := cmd.Flags().GetString("project")
+
+		if taskID == dependsOn {
+			fmt.Fprintf(os.Stderr, "Error: Task '%s' cannot depend on itself\n", taskID)
+			os.Exit(1)
+		}
+
+		if err := changeDependency(projectID, taskID, dependsOn, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Task '%s' now depends on '%s'\n", taskID, dependsOn)
+		fmt.Printf("\n🎯 HØJRE HEGN - NEXT ACTIONS:\n")
+		fmt.Printf("  dppm task dependency check %s  # Verify the dependency chain\n", taskID)
+	},
+}
+
+var taskDependencyRemoveCmd = &cobra.Command{
+	Use:   "remove [task-id] [depends-on-id]",
+	Short: "Remove a dependency",
+	Long: `Remove a Task Dependency
+
+Removes the dependency of task-id on depends-on-id and updates the
+blocked_by/blocking fields of both tasks.
+
+Examples:
+  dppm task dependency remove T1.2 T1.1
+  dppm task dependency remove T2.1 T1.3 --project web-app`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+
+		if err := changeDependency(projectID, args[0], args[1], false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Task '%s' no longer depends on '%s'\n", args[0], args[1])
+	},
+}
+
+var taskDependencyCheckCmd = &cobra.Command{
+	Use:   "check [task-id]",
+	Short: "Show what a task waits for and what waits for it",
+	Long: `Check Task Dependencies
+
+Shows the dependencies of a task with their status, whether the task is
+currently blocked, and which tasks are waiting for it. Dangling references to
+tasks that no longer exist are reported.
+
+Examples:
+  dppm task dependency check T1.2
+  dppm task dependency check T2.1 --project web-app`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+
+		task, err := loadTaskForCommand(projectID, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found\n", args[0])
+			os.Exit(1)
+		}
+
+		tasks, err := loadProjectTasks(task.ProjectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+
+		checkTaskDependencies(*task, tasks)
+	},
+}
+
+// changeDependency adds or removes the edge taskID → dependsOnID and keeps the
+// denormalized fields of both tasks in sync
+func changeDependency(projectID, taskID, dependsOnID string, add bool) error {
+	task, err := loadTaskForCommand(projectID, taskID)
+	if err != nil {
+		return fmt.Errorf("task '%s' not found", taskID)
+	}
+	projectID, taskID = task.ProjectID, task.ID
+
+	unlock, err := store.Lock(projectID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Re-read everything under the lock
+	tasks, err := loadProjectTasks(projectID)
+	if err != nil {
+		return err
+	}
+	taskMap := buildTaskMap(tasks)
+
+	// Old IDs kept as aliases by 'dppm task move' still name the task
+	if _, exists := taskMap[dependsOnID]; !exists {
+		if moved := findTaskByAlias(tasks, dependsOnID); moved != nil {
+			dependsOnID = moved.ID
+		}
+	}
+
+	dependent, exists := taskMap[taskID]
+	if !exists {
+		return fmt.Errorf("task '%s' not found in project '%s'", taskID, projectID)
+	}
+	dependency, exists := taskMap[dependsOnID]
+	if !exists && add {
+		return fmt.Errorf("task '%s' not found in project '%s' (dependencies must be in the same project)", dependsOnID, projectID)
+	}
+
+	if add {
+		if containsString(dependent.DependencyIDs, dependsOnID) {
+			return fmt.Errorf("task '%s' already depends on '%s'", taskID, dependsOnID)
+		}
+		if path := findDependencyPath(taskMap, dependsOnID, taskID); path != nil {
+			return fmt.Errorf("dependency would create a cycle: %s → %s", taskID, strings.Join(path, " → "))
+		}
+	} else if !containsString(dependent.DependencyIDs, dependsOnID) {
+		return fmt.Errorf("task '%s' does not depend on '%s'", taskID, dependsOnID)
+	}
+
+	before := strings.Join(dependent.DependencyIDs, ", ")
+	if add {
+		dependent.DependencyIDs = append(dependent.DependencyIDs, dependsOnID)
+		dependent.BlockedBy = addString(dependent.BlockedBy, dependsOnID)
+	} else {
+		dependent.DependencyIDs = removeString(dependent.DependencyIDs, dependsOnID)
+		dependent.BlockedBy = removeString(dependent.BlockedBy, dependsOnID)
+	}
+	recordChange(&dependent, "dependency_ids", before, strings.Join(dependent.DependencyIDs, ", "))
+	dependent.Updated = nowTimestamp()
+
+	if err := store.SaveTask(&dependent); err != nil {
+		return fmt.Errorf("failed to save task '%s': %v", taskID, err)
+	}
+
+	// The dependency may already be gone when removing a dangling reference
+	if exists {
+		if add {
+			dependency.Blocking = addString(dependency.Blocking, taskID)
+		} else {
+			dependency.Blocking = removeString(dependency.Blocking, taskID)
+		}
+		dependency.Updated = nowTimestamp()
+		if err := store.SaveTask(&dependency); err != nil {
+			return fmt.Errorf("failed to save task '%s': %v", dependsOnID, err)
+		}
+	}
+
+	return nil
+}
+
+func checkTaskDependencies(task Task, tasks []Task) {
+	if printStructured(buildDependencyReport(task, tasks)) {
+		return
+	}
+
+	taskMap := buildTaskMap(tasks)
+
+	fmt.Printf("Dependencies: %s\n", task.ID)
+	fmt.Println("================")
+	fmt.Printf("Title: %s\n", task.Title)
+	fmt.Printf("Status: %s\n", task.Status)
+
+	if len(task.DependencyIDs) == 0 {
+		fmt.Println("\nDepends on: nothing")
+	} else {
+		fmt.Println("\nDepends on:")
+		for _, depID := range task.DependencyIDs {
+			depTask, exists := taskMap[depID]
+			if !exists {
+				fmt.Printf("  ⚠️  %s (missing task)\n", depID)
+				continue
+			}
+			status := "✅"
+			if depTask.Status != "done" {
+				status = "❌"
+			}
+			fmt.Printf("  %s %s - %s (%s)\n", status, depTask.ID, depTask.Title, depTask.Status)
+		}
+	}
+
+	var dependents []Task
+	for _, t := range tasks {
+		if containsString(t.DependencyIDs, task.ID) {
+			dependents = append(dependents, t)
+		}
+	}
+	if len(dependents) > 0 {
+		fmt.Println("\nWaiting for this task:")
+		for _, t := range dependents {
+			fmt.Printf("  • %s - %s (%s)\n", t.ID, t.Title, t.Status)
+		}
+	}
+
+	fmt.Println()
+	if isTaskBlocked(task, tasks) {
+		fmt.Printf("🚫 Blocked by: %s\n", strings.Join(getBlockingTasks(task, tasks), ", "))
+	} else if task.Status != "done" {
+		fmt.Println("✅ Ready - all dependencies are done")
+	}
+}
+
+// buildTaskMap indexes tasks by ID
+func buildTaskMap(tasks []Task) map[string]Task {
+	taskMap := make(map[string]Task)
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+	}
+	return taskMap
+}
+
+// findDependencyPath walks DependencyIDs depth-first from 'from' and returns
+// the path to 'to', or nil when 'to' is not reachable
+func findDependencyPath(taskMap map[string]Task, from, to string) []string {
+	visited := make(map[string]bool)
+
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == to {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		for _, depID := range taskMap[id].DependencyIDs {
+			if path := walk(depID); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+
+	return walk(from)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func addString(list []string, value string) []string {
+	if containsString(list, value) {
+		return list
+	}
+	return append(list, value)
+}
+
+func removeString(list []string, value string) []string {
+	var result []string
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func init() {
+	taskDependencyAddCmd.Flags().String("depends-on", "", "Task that must be completed first (required)")
+	taskDependencyAddCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskDependencyAddCmd.MarkFlagRequired("depends-on")
+
+	taskDependencyRemoveCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskDependencyCheckCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+
+	taskDependencyCmd.AddCommand(taskDependencyAddCmd)
+	taskDependencyCmd.AddCommand(taskDependencyRemoveCmd)
+	taskDependencyCmd.AddCommand(taskDependencyCheckCmd)
+	taskCmd.AddCommand(taskDependencyCmd)
+}
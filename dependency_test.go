@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindDependencyPath(t *testing.T) {
+	taskMap := buildTaskMap([]Task{
+		{ID: "T1.1"},
+		{ID: "T1.2", DependencyIDs: []string{"T1.1"}},
+		{ID: "T1.3", DependencyIDs: []string{"T1.2", "T1.1"}},
+		{ID: "T1.4", DependencyIDs: []string{"T1.9"}},
+	})
+	tests := []struct {
+		from, to string
+		want     []string
+	}{
+		{"T1.3", "T1.1", []string{"T1.3", "T1.2", "T1.1"}},
+		{"T1.2", "T1.1", []string{"T1.2", "T1.1"}},
+		{"T1.1", "T1.3", nil},
+		{"T1.4", "T1.1", nil},
+		{"T1.1", "T1.1", []string{"T1.1"}},
+	}
+	for _, test := range tests {
+		if got := findDependencyPath(taskMap, test.from, test.to); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("path %s → %s = %v, want %v", test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestChangeDependencyRejectsCycles(t *testing.T) {
+	store = NewMemoryStore()
+	for _, id := range []string{"T1.1", "T1.2", "T1.3"} {
+		if err := store.SaveTask(&Task{ID: id, ProjectID: "demo", PhaseID: "P1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := changeDependency("demo", "T1.2", "T1.1", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := changeDependency("demo", "T1.3", "T1.2", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		taskID, dependsOnID string
+		message             string
+	}{
+		{"T1.1", "T1.3", "cycle: T1.1 → T1.3 → T1.2 → T1.1"},
+		{"T1.1", "T1.1", "cycle"},
+		{"T1.2", "T1.1", "already depends on"},
+		{"T1.2", "T1.9", "not found"},
+	}
+	for _, test := range tests {
+		err := changeDependency("demo", test.taskID, test.dependsOnID, true)
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("%s → %s: error %v, want %q", test.taskID, test.dependsOnID, err, test.message)
+		}
+	}
+
+	dependent, _ := store.LoadTask("demo", "T1.2")
+	dependency, _ := store.LoadTask("demo", "T1.1")
+	if !reflect.DeepEqual(dependent.BlockedBy, []string{"T1.1"}) || !reflect.DeepEqual(dependency.Blocking, []string{"T1.2"}) {
+		t.Errorf("blocked_by %v, blocking %v", dependent.BlockedBy, dependency.Blocking)
+	}
+
+	if err := changeDependency("demo", "T1.2", "T1.1", false); err != nil {
+		t.Fatal(err)
+	}
+	dependent, _ = store.LoadTask("demo", "T1.2")
+	dependency, _ = store.LoadTask("demo", "T1.1")
+	if len(dependent.DependencyIDs) != 0 || len(dependent.BlockedBy) != 0 || len(dependency.Blocking) != 0 {
+		t.Errorf("after removal: depends on %v, blocked_by %v, blocking %v", dependent.DependencyIDs, dependent.BlockedBy, dependency.Blocking)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// graphProject is one project's tasks, the unit the graph renderers work on
+type graphProject struct {
+	ID    string
+	Tasks []Task
+}
+
+// statusColors maps task status to node fill colour in both DOT and Mermaid output
+var statusColors = map[string]string{
+	"todo":        "#e0e0e0",
+	"in_progress": "#ffd966",
+	"review":      "#9fc5e8",
+	"blocked":     "#f4cccc",
+	"done":        "#93c47d",
+}
+
+const blockedBorderColor = "#cc0000"
+
+// graphNodeID builds an identifier that is valid in both DOT and Mermaid.
+// Bytes other than ASCII letters and digits, and a leading digit, are escaped
+// as _XX and parts are joined by "__", so IDs such as T1.1 and T1_1 never
+// share a node.
+func graphNodeID(parts ...string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteString("__")
+		}
+		for _, c := range []byte(part) {
+			isDigit := c >= '0' && c <= '9'
+			isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			if isLetter || (isDigit && b.Len() > 0) {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "_%02X", c)
+			}
+		}
+	}
+	return b.String()
+}
+
+func statusColor(status string) string {
+	if color, exists := statusColors[status]; exists {
+		return color
+	}
+	return "#ffffff"
+}
+
+// groupTasksByPhase returns phase IDs in sorted order with their tasks;
+// tasks without a phase are grouped under the empty phase ID
+func groupTasksByPhase(tasks []Task) ([]string, map[string][]Task) {
+	byPhase := make(map[string][]Task)
+	for _, task := range tasks {
+		byPhase[task.PhaseID] = append(byPhase[task.PhaseID], task)
+	}
+
+	var phaseIDs []string
+	for phaseID := range byPhase {
+		phaseIDs = append(phaseIDs, phaseID)
+	}
+	sort.Strings(phaseIDs)
+
+	for _, phaseID := range phaseIDs {
+		phaseTasks := byPhase[phaseID]
+		sort.Slice(phaseTasks, func(i, j int) bool { return phaseTasks[i].ID < phaseTasks[j].ID })
+	}
+
+	return phaseIDs, byPhase
+}
+
+// renderDependencyDOT renders the dependency graph in Graphviz DOT format.
+// Edges point from a dependency to the task waiting for it.
+func renderDependencyDOT(projects []graphProject) string {
+	var b strings.Builder
+
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for _, project := range projects {
+		indent := "  "
+		if len(projects) > 1 {
+			fmt.Fprintf(&b, "  subgraph %s {\n", graphNodeID("cluster", project.ID))
+			fmt.Fprintf(&b, "    label=%s;\n", dotQuote(project.ID))
+			indent = "    "
+		}
+
+		phaseIDs, byPhase := groupTasksByPhase(project.Tasks)
+		for _, phaseID := range phaseIDs {
+			inner := indent
+			if phaseID != "" {
+				fmt.Fprintf(&b, "%ssubgraph %s {\n", indent, graphNodeID("cluster", project.ID, phaseID))
+				fmt.Fprintf(&b, "%s  label=%s;\n", indent, dotQuote(phaseID))
+				fmt.Fprintf(&b, "%s  style=dashed;\n", indent)
+				inner = indent + "  "
+			}
+
+			for _, task := range byPhase[phaseID] {
+				attrs := fmt.Sprintf("label=%s, fillcolor=%s", dotQuote(task.ID+"\n"+task.Title), dotQuote(statusColor(task.Status)))
+				if task.Status != "done" && isTaskBlocked(task, project.Tasks) {
+					attrs += fmt.Sprintf(", color=%s, penwidth=2", dotQuote(blockedBorderColor))
+				}
+				fmt.Fprintf(&b, "%s%s [%s];\n", inner, graphNodeID(project.ID, task.ID), attrs)
+			}
+
+			if phaseID != "" {
+				fmt.Fprintf(&b, "%s}\n", indent)
+			}
+		}
+
+		taskMap := buildTaskMap(project.Tasks)
+		for _, task := range project.Tasks {
+			for _, depID := range task.DependencyIDs {
+				if _, exists := taskMap[depID]; !exists {
+					continue
+				}
+				fmt.Fprintf(&b, "%s%s -> %s;\n", indent, graphNodeID(project.ID, depID), graphNodeID(project.ID, task.ID))
+			}
+		}
+
+		if len(projects) > 1 {
+			b.WriteString("  }\n")
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// renderDependencyMermaid renders the dependency graph as a Mermaid flowchart
+func renderDependencyMermaid(projects []graphProject) string {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	var nodeClasses, nodeStyles []string
+	for _, project := range projects {
+		indent := "  "
+		if len(projects) > 1 {
+			fmt.Fprintf(&b, "  subgraph %s [%s]\n", graphNodeID("project", project.ID), mermaidQuote(project.ID))
+			indent = "    "
+		}
+
+		phaseIDs, byPhase := groupTasksByPhase(project.Tasks)
+		for _, phaseID := range phaseIDs {
+			inner := indent
+			if phaseID != "" {
+				fmt.Fprintf(&b, "%ssubgraph %s [%s]\n", indent, graphNodeID("phase", project.ID, phaseID), mermaidQuote(phaseID))
+				inner = indent + "  "
+			}
+
+			for _, task := range byPhase[phaseID] {
+				nodeID := graphNodeID(project.ID, task.ID)
+				fmt.Fprintf(&b, "%s%s[%s]\n", inner, nodeID, mermaidQuote(task.ID+": "+task.Title))
+
+				nodeClasses = append(nodeClasses, fmt.Sprintf("  class %s %s", nodeID, mermaidStatusClass(task.Status)))
+				if task.Status != "done" && isTaskBlocked(task, project.Tasks) {
+					// Only the border marks blocked tasks, so the status fill stays visible
+					nodeStyles = append(nodeStyles, fmt.Sprintf("  style %s stroke:%s,stroke-width:2px", nodeID, blockedBorderColor))
+				}
+			}
+
+			if phaseID != "" {
+				fmt.Fprintf(&b, "%send\n", indent)
+			}
+		}
+
+		taskMap := buildTaskMap(project.Tasks)
+		for _, task := range project.Tasks {
+			for _, depID := range task.DependencyIDs {
+				if _, exists := taskMap[depID]; !exists {
+					continue
+				}
+				fmt.Fprintf(&b, "%s%s --> %s\n", indent, graphNodeID(project.ID, depID), graphNodeID(project.ID, task.ID))
+			}
+		}
+
+		if len(projects) > 1 {
+			b.WriteString("  end\n")
+		}
+	}
+
+	for _, status := range []string{"todo", "in_progress", "review", "blocked", "done"} {
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:#666666\n", mermaidStatusClass(status), statusColor(status))
+	}
+	fmt.Fprintf(&b, "  classDef other fill:%s,stroke:#666666\n", statusColor(""))
+	for _, line := range append(nodeClasses, nodeStyles...) {
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
+// mermaidStatusClass maps a status to a class name; Mermaid reserves some words
+func mermaidStatusClass(status string) string {
+	if _, exists := statusColors[status]; !exists {
+		return "other"
+	}
+	return "status_" + status
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + s + `"`
+}
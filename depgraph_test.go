@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphNodeIDKeepsIDsApart(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"web", "T1.1"}, "web__T1_2E1"},
+		{[]string{"web", "T1_1"}, "web__T1_5F1"},
+		{[]string{"web_T1", "1"}, "web_5FT1__1"},
+		{[]string{"2026-app", "T1.1"}, "_32026_2Dapp__T1_2E1"},
+	}
+	seen := make(map[string]bool)
+	for _, test := range tests {
+		got := graphNodeID(test.parts...)
+		if got != test.want {
+			t.Errorf("graphNodeID(%q) = %s, want %s", test.parts, got, test.want)
+		}
+		if seen[got] {
+			t.Errorf("graphNodeID(%q) = %s is shared with another ID", test.parts, got)
+		}
+		seen[got] = true
+	}
+}
+
+func TestDependencyGraphsKeepSimilarTaskIDsApart(t *testing.T) {
+	projects := []graphProject{{ID: "web", Tasks: []Task{
+		{ID: "T1.1", PhaseID: "P1", Title: "Dotted", Status: "done"},
+		{ID: "T1_1", PhaseID: "P1", Title: "Underscored", Status: "todo", DependencyIDs: []string{"T1.1"}},
+	}}}
+
+	dot := renderDependencyDOT(projects)
+	for _, line := range []string{"web__T1_2E1 [", "web__T1_5F1 [", "web__T1_2E1 -> web__T1_5F1;"} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("DOT output lacks %q:\n%s", line, dot)
+		}
+	}
+
+	mermaid := renderDependencyMermaid(projects)
+	for _, line := range []string{"class web__T1_2E1 status_done", "class web__T1_5F1 status_todo", "web__T1_2E1 --> web__T1_5F1"} {
+		if !strings.Contains(mermaid, line) {
+			t.Errorf("Mermaid output lacks %q:\n%s", line, mermaid)
+		}
+	}
+}
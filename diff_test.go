@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			name:   "equal",
+			before: "a\nb\n",
+			after:  "a\nb\n",
+			want:   "",
+		},
+		{
+			name:   "changed line",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want:   "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:   "removed block with context",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			after:  "1\n2\n3\n4\n7\n8\n9\n",
+			want:   "--- old\n+++ new\n@@ -2,8 +2,6 @@\n 2\n 3\n 4\n-5\n-6\n 7\n 8\n 9\n",
+		},
+		{
+			name:   "from empty",
+			before: "",
+			after:  "a\nb\n",
+			want:   "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:   "to empty",
+			before: "a\n",
+			after:  "",
+			want:   "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:   "missing newline at end of file",
+			before: "a\nb\n",
+			after:  "a\nb",
+			want:   "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", test.before, test.after); got != test.want {
+				t.Errorf("diff:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffSplitsDistantHunks(t *testing.T) {
+	var before []string
+	for i := 1; i <= 30; i++ {
+		before = append(before, fmt.Sprint(i))
+	}
+	after := append([]string(nil), before...)
+	after[1] = "two"
+	after[24] = "twenty-five"
+
+	got := unifiedDiff("old", "new", strings.Join(before, "\n")+"\n", strings.Join(after, "\n")+"\n")
+	want := "--- old\n+++ new\n" +
+		"@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+		"@@ -22,7 +22,7 @@\n 22\n 23\n 24\n-25\n+twenty-five\n 26\n 27\n 28\n"
+	if got != want {
+		t.Errorf("diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffLinesIsMinimal(t *testing.T) {
+	a := splitLines("a\nb\nc\na\nb\nb\na\n")
+	b := splitLines("c\nb\na\nb\na\nc\n")
+
+	script := diffLines(a, b)
+	edits := 0
+	var gotA, gotB []string
+	for _, line := range script {
+		switch line.op {
+		case ' ':
+			gotA = append(gotA, line.text)
+			gotB = append(gotB, line.text)
+		case '-':
+			gotA = append(gotA, line.text)
+			edits++
+		case '+':
+			gotB = append(gotB, line.text)
+			edits++
+		}
+	}
+	if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+		t.Fatalf("edit script does not turn a into b: %v", script)
+	}
+	// The classic example from Myers' paper needs five edits
+	if edits != 5 {
+		t.Errorf("edit script has %d edits, want 5", edits)
+	}
+}
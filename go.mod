@@ -5,10 +5,12 @@ go 1.22.5
 require (
 	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/spf13/cobra v1.10.1
+	go.etcd.io/bbolt v1.3.11
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
+	golang.org/x/sys v0.29.0 // indirect
 )
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// HistoryEntry records one field change on a task. Entries are only ever
+// appended, never edited or removed.
+type HistoryEntry struct {
+	Timestamp string `yaml:"timestamp" json:"timestamp"`
+	Author    string `yaml:"author" json:"author"`
+	Field     string `yaml:"field" json:"field"`
+	From      string `yaml:"from,omitempty" json:"from,omitempty"`
+	To        string `yaml:"to,omitempty" json:"to,omitempty"`
+	// Forced marks changes that bypassed the project workflow with --force
+	Forced bool `yaml:"forced,omitempty" json:"forced,omitempty"`
+}
+
+var taskHistoryCmd = &cobra.Command{
+	Use:   "history [task-id]",
+	Short: "Show the change history of a task",
+	Long: `Show Task Change History
+
+Every change made through dppm is appended to the task's history section,
+recording when it happened, who made it, and the old and new value.
+
+The author is taken from the DPPM_AUTHOR environment variable, then the
+'author' setting in ~/.dppm/config.yaml, falling back to the current system user.
+
+Arguments:
+  task-id    Task identifier
+
+Examples:
+  dppm task history T1.1
+  dppm task history T1.1 --project web-app
+  DPPM_AUTHOR=gemini dppm task update T1.1 --status review`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+
+		task, err := loadTaskForCommand(projectID, taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
+			os.Exit(1)
+		}
+
+		displayTaskHistory(*task)
+	},
+}
+
+// TaskHistory is the machine-readable form of 'dppm task history'
+type TaskHistory struct {
+	TaskID   string         `yaml:"task_id" json:"task_id"`
+	Created  string         `yaml:"created" json:"created"`
+	Reporter string         `yaml:"reporter,omitempty" json:"reporter,omitempty"`
+	History  []HistoryEntry `yaml:"history" json:"history"`
+}
+
+func displayTaskHistory(task Task) {
+	history := task.History
+	if history == nil {
+		history = []HistoryEntry{}
+	}
+	if printStructured(TaskHistory{TaskID: task.ID, Created: task.Created, Reporter: task.Reporter, History: history}) {
+		return
+	}
+
+	fmt.Printf("History: %s\n", task.ID)
+	fmt.Printf("================\n\n")
+	fmt.Printf("Created: %s\n", task.Created)
+	if task.Reporter != "" {
+		fmt.Printf("Reporter: %s\n", task.Reporter)
+	}
+	fmt.Println()
+
+	if len(task.History) == 0 {
+		fmt.Println("No recorded changes.")
+		return
+	}
+
+	for _, entry := range task.History {
+		line := fmt.Sprintf("%s  %-12s %s: %s → %s", entry.Timestamp, entry.Author, entry.Field, historyValue(entry.From), historyValue(entry.To))
+		if entry.Forced {
+			line += "  ⚠️  forced"
+		}
+		fmt.Println(line)
+	}
+}
+
+func historyValue(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	return truncate(strings.ReplaceAll(value, "\n", " "), 60)
+}
+
+// recordChange appends a history entry when a field actually changes
+func recordChange(task *Task, field, from, to string) {
+	if from == to {
+		return
+	}
+	task.History = append(task.History, HistoryEntry{
+		Timestamp: nowTimestamp(),
+		Author:    currentAuthor(),
+		Field:     field,
+		From:      from,
+		To:        to,
+	})
+}
+
+// currentAuthor identifies who is running dppm, for history and reporter fields
+func currentAuthor() string {
+	if author := strings.TrimSpace(os.Getenv("DPPM_AUTHOR")); author != "" {
+		return author
+	}
+	if author := strings.TrimSpace(loadUserConfig().Author); author != "" {
+		return author
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "dppm-user"
+}
+
+func init() {
+	taskHistoryCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+
+	taskCmd.AddCommand(taskHistoryCmd)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the local query index",
+	Long: `Local Query Index
+
+Listing projects, phases and tasks (status, ready/blocked, dependency and
+next-id queries) is served from an embedded database in ~/.dppm/index.db
+instead of re-parsing every YAML file in Dropbox. A tasks folder whose
+modification time did not change is read from the index without listing
+it; in a changed folder only files whose modification time or size changed
+are parsed again, so the index refreshes itself on use.
+
+dppm and Dropbox replace files by renaming them, which marks the folder as
+changed. A task file edited in place by hand is noticed within 10 minutes,
+or immediately after 'dppm index rebuild'.
+
+The YAML files stay the source of truth: the index can be deleted or rebuilt
+at any time without losing data. Set DPPM_NO_INDEX=1 to bypass it.
+
+Available Commands:
+  status    Show what the index holds and how much of it is stale
+  rebuild   Drop the index and index all projects again
+
+Examples:
+  dppm index status
+  dppm index rebuild
+  DPPM_NO_INDEX=1 dppm status project web-app`,
+}
+
+var indexStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what the index holds",
+	Long: `Index Status
+
+Shows the location and size of the index, how many projects, phases and tasks
+it holds, and how many entries are stale (changed on disk since they were
+indexed; they are refreshed on the next query that touches them).
+
+Examples:
+  dppm index status
+  dppm index status --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		indexed := requireIndexedStore()
+
+		stats, err := indexed.Stats()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(stats) {
+			return
+		}
+		printIndexStats(stats)
+	},
+}
+
+var indexRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the index from the YAML files",
+	Long: `Rebuild the Index
+
+Deletes the index and parses every project, phase and task file again.
+Only needed if the index is suspected to be wrong; normal use keeps it
+up to date on its own.
+
+Examples:
+  dppm index rebuild`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		indexed := requireIndexedStore()
+
+		stats, err := indexed.Rebuild()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(stats) {
+			return
+		}
+		fmt.Println("✅ Index rebuilt")
+		printIndexStats(stats)
+	},
+}
+
+// requireIndexedStore returns the active store as an IndexedStore, or exits
+// when the index is disabled
+func requireIndexedStore() *IndexedStore {
+	indexed, ok := store.(*IndexedStore)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Error: The index is disabled (DPPM_NO_INDEX is set)")
+		os.Exit(1)
+	}
+	return indexed
+}
+
+func printIndexStats(stats IndexStats) {
+	fmt.Printf("Index: %s (%d KB)\n", stats.Path, (stats.Size+1023)/1024)
+	fmt.Printf("  Projects: %d\n", stats.Projects)
+	fmt.Printf("  Phases:   %d\n", stats.Phases)
+	fmt.Printf("  Tasks:    %d\n", stats.Tasks)
+	if stats.Stale > 0 {
+		fmt.Printf("  Stale:    %d (refreshed on next use)\n", stats.Stale)
+	}
+}
+
+func init() {
+	indexCmd.AddCommand(indexStatusCmd)
+	indexCmd.AddCommand(indexRebuildCmd)
+}
@@ -0,0 +1,545 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// indexVersion changes whenever the layout of the index changes; an index
+// with another version is discarded and rebuilt
+const indexVersion = 2
+
+// indexVerifyInterval is how long a tasks folder with an unchanged
+// modification time is trusted without looking at its files. dppm and Dropbox
+// replace files by renaming, which touches the folder; an editor saving a file
+// in place does not, so such edits show up after this interval at the latest.
+// While a project lock is held every file is checked regardless.
+const indexVerifyInterval = 10 * time.Minute
+
+// Kinds of files kept in the index
+const (
+	indexKindProject = "project"
+	indexKindPhase   = "phase"
+	indexKindTask    = "task"
+)
+
+// Buckets of the index database
+var (
+	indexMetaBucket  = []byte("meta")
+	indexFilesBucket = []byte("files")
+	indexDirsBucket  = []byte("dirs")
+)
+
+// IndexEntry is one parsed file, stored under "<kind>:<path>" with the path
+// relative to the store root. It is valid as long as the file still has the
+// recorded modification time and size.
+type IndexEntry struct {
+	ModTime int64           `json:"mod_time"`
+	Size    int64           `json:"size"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// IndexDir is one tasks folder: its modification time when it was last read,
+// when its files were last checked, and the task files it held
+type IndexDir struct {
+	ModTime  int64    `json:"mod_time"`
+	Verified int64    `json:"verified"`
+	Files    []string `json:"files"`
+}
+
+// IndexedStore is the YAML store with a rebuildable bbolt index in ~/.dppm.
+// Tasks folders whose modification time did not change are served from the
+// index without listing them; in changed folders only files whose
+// modification time or size changed are parsed again. Writes go straight to
+// the YAML files, which stay the source of truth.
+type IndexedStore struct {
+	*YAMLStore
+	indexPath string
+	locks     int32 // project locks currently held by this process
+}
+
+// NewIndexedStore wraps a YAML store with the index kept at indexPath
+func NewIndexedStore(yamlStore *YAMLStore, indexPath string) *IndexedStore {
+	return &IndexedStore{YAMLStore: yamlStore, indexPath: indexPath}
+}
+
+// getIndexPath returns the location of the index database
+func getIndexPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".dppm", "index.db"), nil
+}
+
+// Lock takes the project lock of the YAML store. While it is held, reads do
+// not trust unchanged tasks folders: whatever is read under the lock decides
+// what gets written, and a stale copy could overwrite an in-place edit or
+// hand out a task ID that is already taken.
+func (s *IndexedStore) Lock(projectID string) (func(), error) {
+	unlock, err := s.YAMLStore.Lock(projectID)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&s.locks, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt32(&s.locks, -1)
+			unlock()
+		})
+	}, nil
+}
+
+// ListProjects returns every project with a readable project.yaml
+func (s *IndexedStore) ListProjects() ([]Project, error) {
+	entries, err := os.ReadDir(s.projectsDir())
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	err = s.withIndex(func(b *indexBatch) error {
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			var project Project
+			if b.file(filepath.Join(s.projectDir(entry.Name()), "project.yaml"), indexKindProject, &project, seen) != nil {
+				continue
+			}
+			projects = append(projects, project)
+		}
+		b.prune(indexKindProject, s.projectsDir(), seen)
+		return nil
+	})
+	if err != nil {
+		return s.YAMLStore.ListProjects()
+	}
+	return projects, nil
+}
+
+// ListPhases returns every phase with a readable phase.yaml
+func (s *IndexedStore) ListPhases(projectID string) ([]Phase, error) {
+	phasesDir := filepath.Join(s.projectDir(projectID), "phases")
+	entries, err := os.ReadDir(phasesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var phases []Phase
+	err = s.withIndex(func(b *indexBatch) error {
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			var phase Phase
+			if b.file(filepath.Join(phasesDir, entry.Name(), "phase.yaml"), indexKindPhase, &phase, seen) != nil {
+				continue
+			}
+			phases = append(phases, phase)
+		}
+		b.prune(indexKindPhase, phasesDir, seen)
+		return nil
+	})
+	if err != nil {
+		return s.YAMLStore.ListPhases(projectID)
+	}
+	return phases, nil
+}
+
+// ListTasks loads all tasks in a project in the same order as the YAML store:
+// phase folders first, then the project-level tasks folder
+func (s *IndexedStore) ListTasks(projectID string) ([]Task, error) {
+	var taskDirs []string
+	phasesDir := filepath.Join(s.projectDir(projectID), "phases")
+	if phaseEntries, err := os.ReadDir(phasesDir); err == nil {
+		for _, phaseEntry := range phaseEntries {
+			if phaseEntry.IsDir() {
+				taskDirs = append(taskDirs, filepath.Join(phasesDir, phaseEntry.Name(), "tasks"))
+			}
+		}
+	}
+	taskDirs = append(taskDirs, s.taskDir(projectID, ""))
+
+	var tasks []Task
+	err := s.withIndex(func(b *indexBatch) error {
+		seen := make(map[string]bool)
+		for _, tasksDir := range taskDirs {
+			tasks = append(tasks, b.tasksIn(tasksDir)...)
+			seen[b.rel(tasksDir)] = true
+		}
+		b.pruneDirs(s.projectDir(projectID), seen)
+		return nil
+	})
+	if err != nil {
+		return s.YAMLStore.ListTasks(projectID)
+	}
+	return tasks, nil
+}
+
+// LoadTask reads a task by its current ID, falling back to the indexed tasks
+// for a task that lists taskID among its aliases
+func (s *IndexedStore) LoadTask(projectID, taskID string) (*Task, error) {
+	task, err := s.loadTaskFile(projectID, taskID)
+	if !isNotFound(err) {
+		return task, err
+	}
+
+	tasks, listErr := s.ListTasks(projectID)
+	if listErr != nil {
+		return nil, listErr
+	}
+	if aliased := findTaskByAlias(tasks, taskID); aliased != nil {
+		return aliased, nil
+	}
+	return nil, err
+}
+
+// Rebuild drops the index and indexes every project, phase and task again
+func (s *IndexedStore) Rebuild() (IndexStats, error) {
+	if err := os.Remove(s.indexPath); err != nil && !os.IsNotExist(err) {
+		return IndexStats{}, err
+	}
+	// Left behind by versions that kept the index in a JSON file
+	os.Remove(filepath.Join(filepath.Dir(s.indexPath), "index.json"))
+
+	projects, err := s.ListProjects()
+	if err != nil {
+		return IndexStats{}, err
+	}
+	for _, project := range projects {
+		if _, err := s.ListPhases(project.ID); err != nil {
+			return IndexStats{}, err
+		}
+		if _, err := s.ListTasks(project.ID); err != nil {
+			return IndexStats{}, err
+		}
+	}
+	return s.Stats()
+}
+
+// IndexStats summarises the index for 'dppm index status'
+type IndexStats struct {
+	Path     string `yaml:"path" json:"path"`
+	Size     int64  `yaml:"size" json:"size"`
+	Projects int    `yaml:"projects" json:"projects"`
+	Phases   int    `yaml:"phases" json:"phases"`
+	Tasks    int    `yaml:"tasks" json:"tasks"`
+	Stale    int    `yaml:"stale" json:"stale"`
+}
+
+// Stats counts the indexed files and how many of them changed on disk since
+func (s *IndexedStore) Stats() (IndexStats, error) {
+	stats := IndexStats{Path: s.indexPath}
+
+	db, err := s.openIndex()
+	if err != nil {
+		return stats, err
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(indexFilesBucket).ForEach(func(key, value []byte) error {
+			kind, rel, _ := strings.Cut(string(key), ":")
+			switch kind {
+			case indexKindProject:
+				stats.Projects++
+			case indexKindPhase:
+				stats.Phases++
+			case indexKindTask:
+				stats.Tasks++
+			}
+			var entry IndexEntry
+			info, err := os.Stat(filepath.Join(s.root, filepath.FromSlash(rel)))
+			if json.Unmarshal(value, &entry) != nil || err != nil || info.ModTime().UnixNano() != entry.ModTime || info.Size() != entry.Size {
+				stats.Stale++
+			}
+			return nil
+		})
+	})
+	if info, err := os.Stat(s.indexPath); err == nil {
+		stats.Size = info.Size()
+	}
+	return stats, err
+}
+
+// openIndex opens the index database, starting it over when it was built by
+// another version or for another projects folder
+func (s *IndexedStore) openIndex() (*bolt.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(s.indexPath), 0755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(s.indexPath, 0644, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index: %v", err)
+	}
+
+	version := []byte(strconv.Itoa(indexVersion))
+	current := false
+	db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(indexMetaBucket)
+		current = meta != nil && string(meta.Get([]byte("version"))) == string(version) &&
+			string(meta.Get([]byte("root"))) == s.root &&
+			tx.Bucket(indexFilesBucket) != nil && tx.Bucket(indexDirsBucket) != nil
+		return nil
+	})
+	if current {
+		return db, nil
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{indexMetaBucket, indexFilesBucket, indexDirsBucket} {
+			if tx.Bucket(name) != nil {
+				if err := tx.DeleteBucket(name); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		meta := tx.Bucket(indexMetaBucket)
+		if err := meta.Put([]byte("version"), version); err != nil {
+			return err
+		}
+		return meta.Put([]byte("root"), []byte(s.root))
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize index: %v", err)
+	}
+	return db, nil
+}
+
+// withIndex runs fn against a read-only view of the index and then writes the
+// entries fn changed in one transaction. Nothing is written when nothing
+// changed. Failing to write only costs speed on the next run, so write errors
+// are reported but not returned; an error means the index could not be read
+// at all.
+func (s *IndexedStore) withIndex(fn func(b *indexBatch) error) error {
+	db, err := s.openIndex()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	b := &indexBatch{s: s, files: make(map[string][]byte), dirs: make(map[string][]byte)}
+	err = db.View(func(tx *bolt.Tx) error {
+		b.tx = tx
+		return fn(b)
+	})
+	if err != nil {
+		return err
+	}
+	if len(b.files) == 0 && len(b.dirs) == 0 {
+		return nil
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for bucket, changes := range map[string]map[string][]byte{string(indexFilesBucket): b.files, string(indexDirsBucket): b.dirs} {
+			target := tx.Bucket([]byte(bucket))
+			for key, value := range changes {
+				var err error
+				if value == nil {
+					err = target.Delete([]byte(key))
+				} else {
+					err = target.Put([]byte(key), value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not update the index %s: %v\n", s.indexPath, err)
+	}
+	return nil
+}
+
+// indexBatch reads the index inside a read-only transaction and collects the
+// changes to write afterwards; a nil value deletes the key
+type indexBatch struct {
+	s     *IndexedStore
+	tx    *bolt.Tx
+	files map[string][]byte
+	dirs  map[string][]byte
+}
+
+// rel returns path relative to the store root, with forward slashes
+func (b *indexBatch) rel(path string) string {
+	rel, err := filepath.Rel(b.s.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// get returns the stored value of key, including changes not yet written
+func (b *indexBatch) get(bucket []byte, pending map[string][]byte, key string) []byte {
+	if value, changed := pending[key]; changed {
+		return value
+	}
+	return b.tx.Bucket(bucket).Get([]byte(key))
+}
+
+// file decodes the file at path into v, from the index when the file is
+// unchanged and by parsing the YAML otherwise
+func (b *indexBatch) file(path, kind string, v interface{}, seen map[string]bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	key := kind + ":" + b.rel(path)
+	seen[key] = true
+
+	var entry IndexEntry
+	if data := b.get(indexFilesBucket, b.files, key); data != nil && json.Unmarshal(data, &entry) == nil &&
+		entry.ModTime == info.ModTime().UnixNano() && entry.Size == info.Size() {
+		if err := json.Unmarshal(entry.Data, v); err == nil {
+			return nil
+		}
+	}
+
+	if err := readYAMLFile(path, v); err != nil {
+		b.files[key] = nil
+		return err
+	}
+	if data, err := json.Marshal(v); err == nil {
+		entry = IndexEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Data: data}
+		if encoded, err := json.Marshal(entry); err == nil {
+			b.files[key] = encoded
+		}
+	}
+	return nil
+}
+
+// cachedTask decodes an indexed task without checking its file
+func (b *indexBatch) cachedTask(rel string, task *Task) bool {
+	var entry IndexEntry
+	data := b.get(indexFilesBucket, b.files, indexKindTask+":"+rel)
+	return data != nil && json.Unmarshal(data, &entry) == nil && json.Unmarshal(entry.Data, task) == nil
+}
+
+// tasksIn returns the tasks of one tasks folder. A folder that did not change
+// since it was last read is served from the index as a whole, unless a project
+// lock is held; otherwise the folder is listed and each file is checked.
+func (b *indexBatch) tasksIn(tasksDir string) []Task {
+	dirRel := b.rel(tasksDir)
+	info, err := os.Stat(tasksDir)
+	if err != nil {
+		b.dropDir(dirRel)
+		return nil
+	}
+
+	var record IndexDir
+	if data := b.get(indexDirsBucket, b.dirs, dirRel); data != nil && json.Unmarshal(data, &record) == nil &&
+		record.ModTime == info.ModTime().UnixNano() && time.Since(time.Unix(0, record.Verified)) < indexVerifyInterval &&
+		atomic.LoadInt32(&b.s.locks) == 0 {
+		tasks := make([]Task, 0, len(record.Files))
+		for _, name := range record.Files {
+			var task Task
+			if !b.cachedTask(dirRel+"/"+name, &task) {
+				tasks = nil
+				break
+			}
+			tasks = append(tasks, task)
+		}
+		if tasks != nil {
+			return tasks
+		}
+	}
+
+	entries, err := os.ReadDir(tasksDir)
+	if err != nil {
+		b.dropDir(dirRel)
+		return nil
+	}
+	var tasks []Task
+	var names []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") || isConflictedCopy(entry.Name()) {
+			continue
+		}
+		// Unreadable files stay listed without an entry, so the folder is
+		// read (and the warning shown) again next time
+		names = append(names, entry.Name())
+		var task Task
+		if err := b.file(filepath.Join(tasksDir, entry.Name()), indexKindTask, &task, seen); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping unreadable task file %s: %v\n", entry.Name(), err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	for _, name := range record.Files {
+		if key := indexKindTask + ":" + dirRel + "/" + name; !seen[key] {
+			b.files[key] = nil
+		}
+	}
+
+	record = IndexDir{ModTime: info.ModTime().UnixNano(), Verified: time.Now().UnixNano(), Files: names}
+	if data, err := json.Marshal(record); err == nil {
+		b.dirs[dirRel] = data
+	}
+	return tasks
+}
+
+// dropDir removes a tasks folder that no longer exists and its tasks
+func (b *indexBatch) dropDir(dirRel string) {
+	data := b.get(indexDirsBucket, b.dirs, dirRel)
+	if data == nil {
+		return
+	}
+	var record IndexDir
+	if json.Unmarshal(data, &record) == nil {
+		for _, name := range record.Files {
+			b.files[indexKindTask+":"+dirRel+"/"+name] = nil
+		}
+	}
+	b.dirs[dirRel] = nil
+}
+
+// pruneDirs drops the tasks folders below dir that were not seen, such as
+// those of deleted or renamed phases
+func (b *indexBatch) pruneDirs(dir string, seen map[string]bool) {
+	prefix := b.rel(dir) + "/"
+	var gone []string
+	cursor := b.tx.Bucket(indexDirsBucket).Cursor()
+	for key, _ := cursor.Seek([]byte(prefix)); key != nil && strings.HasPrefix(string(key), prefix); key, _ = cursor.Next() {
+		if !seen[string(key)] {
+			gone = append(gone, string(key))
+		}
+	}
+	for _, dirRel := range gone {
+		b.dropDir(dirRel)
+	}
+}
+
+// prune drops the entries of the given kind below dir that were not seen
+// while listing it, so deleted and moved files leave the index
+func (b *indexBatch) prune(kind, dir string, seen map[string]bool) {
+	prefix := kind + ":" + b.rel(dir) + "/"
+	cursor := b.tx.Bucket(indexFilesBucket).Cursor()
+	for key, _ := cursor.Seek([]byte(prefix)); key != nil && strings.HasPrefix(string(key), prefix); key, _ = cursor.Next() {
+		if !seen[string(key)] {
+			b.files[string(key)] = nil
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newTestIndexedStore(t *testing.T) (*IndexedStore, string) {
+	t.Helper()
+	root := t.TempDir()
+	s := NewIndexedStore(NewYAMLStore(root), filepath.Join(t.TempDir(), "index.db"))
+
+	if err := s.SaveProject(&Project{ID: "demo", Name: "Demo"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, phaseID := range []string{"P1", "P2"} {
+		if err := s.SavePhase(&Phase{ID: phaseID, ProjectID: "demo", Name: phaseID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, task := range []Task{
+		{ID: "T1.1", ProjectID: "demo", PhaseID: "P1", Title: "First"},
+		{ID: "T1.2", ProjectID: "demo", PhaseID: "P1", Title: "Second"},
+		{ID: "T2.1", ProjectID: "demo", PhaseID: "P2", Title: "Third"},
+	} {
+		task := task
+		if err := s.SaveTask(&task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s, root
+}
+
+func listTaskTitles(t *testing.T, s *IndexedStore) map[string]string {
+	t.Helper()
+	tasks, err := s.ListTasks("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make(map[string]string)
+	for _, task := range tasks {
+		titles[task.ID] = task.Title
+	}
+	return titles
+}
+
+func TestIndexedStoreFollowsChanges(t *testing.T) {
+	s, _ := newTestIndexedStore(t)
+
+	if got := listTaskTitles(t, s); len(got) != 3 || got["T1.2"] != "Second" {
+		t.Fatalf("initial listing = %v", got)
+	}
+
+	task, err := s.LoadTask("demo", "T1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Title = "Renamed"
+	if err := s.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteTask("demo", "T2.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := listTaskTitles(t, s)
+	if len(got) != 2 || got["T1.2"] != "Renamed" {
+		t.Errorf("listing after update and delete = %v", got)
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Tasks != 2 || stats.Stale != 0 {
+		t.Errorf("stats = %+v, want 2 tasks and nothing stale", stats)
+	}
+}
+
+func TestIndexedStoreSkipsUnchangedFolders(t *testing.T) {
+	s, root := newTestIndexedStore(t)
+	listTaskTitles(t, s)
+
+	// Rewrite a task in place, as an editor would, keeping both modification times
+	tasksDir := filepath.Join(root, "projects", "demo", "phases", "P1", "tasks")
+	path := filepath.Join(tasksDir, "T1.1.yaml")
+	fileInfo, _ := os.Stat(path)
+	dirInfo, _ := os.Stat(tasksDir)
+	if err := os.WriteFile(path, []byte("id: T1.1\ntitle: Edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, fileInfo.ModTime(), fileInfo.ModTime())
+	os.Chtimes(tasksDir, dirInfo.ModTime(), dirInfo.ModTime())
+
+	if got := listTaskTitles(t, s); got["T1.1"] != "First" {
+		t.Errorf("unchanged folder was read again: T1.1 = %q", got["T1.1"])
+	}
+
+	// Once the folder is due for verification the edit is picked up
+	forceIndexVerification(t, s)
+	if got := listTaskTitles(t, s); got["T1.1"] != "Edited" {
+		t.Errorf("edit not picked up after verification: %v", got)
+	}
+}
+
+func TestIndexedStoreChecksFilesUnderLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s, root := newTestIndexedStore(t)
+	listTaskTitles(t, s)
+
+	tasksDir := filepath.Join(root, "projects", "demo", "phases", "P1", "tasks")
+	path := filepath.Join(tasksDir, "T1.1.yaml")
+	fileInfo, _ := os.Stat(path)
+	dirInfo, _ := os.Stat(tasksDir)
+	if err := os.WriteFile(path, []byte("id: T1.1\ntitle: Edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, fileInfo.ModTime().Add(time.Second), fileInfo.ModTime().Add(time.Second))
+	os.Chtimes(tasksDir, dirInfo.ModTime(), dirInfo.ModTime())
+
+	unlock, err := s.Lock("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listTaskTitles(t, s); got["T1.1"] != "Edited" {
+		t.Errorf("read under the lock served the cached folder: T1.1 = %q", got["T1.1"])
+	}
+	unlock()
+	unlock()
+	if s.locks != 0 {
+		t.Errorf("%d locks still counted after unlocking", s.locks)
+	}
+}
+
+// forceIndexVerification marks every indexed folder as last checked long ago
+func forceIndexVerification(t *testing.T, s *IndexedStore) {
+	t.Helper()
+	err := s.withIndex(func(b *indexBatch) error {
+		return b.tx.Bucket(indexDirsBucket).ForEach(func(key, value []byte) error {
+			var record IndexDir
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			record.Verified = time.Now().Add(-2 * indexVerifyInterval).UnixNano()
+			data, err := json.Marshal(record)
+			b.dirs[string(key)] = data
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexedStoreListsPhasesAndProjects(t *testing.T) {
+	s, root := newTestIndexedStore(t)
+
+	phases, err := s.ListPhases("demo")
+	if err != nil || len(phases) != 2 {
+		t.Fatalf("ListPhases = %v, %v", phases, err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "projects", "demo", "phases", "P2")); err != nil {
+		t.Fatal(err)
+	}
+	phases, _ = s.ListPhases("demo")
+	tasks, _ := s.ListTasks("demo")
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	sort.Strings(ids)
+	if len(phases) != 1 || len(ids) != 2 || ids[0] != "T1.1" || ids[1] != "T1.2" {
+		t.Errorf("after removing P2: %d phases, tasks %v", len(phases), ids)
+	}
+
+	projects, err := s.ListProjects()
+	if err != nil || len(projects) != 1 || projects[0].Name != "Demo" {
+		t.Errorf("ListProjects = %v, %v", projects, err)
+	}
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	validIssueTypes      = []string{"bug", "enhancement", "change_request"}
+	validIssueStatuses   = []string{"todo", "in_progress", "done", "closed"}
+	validIssueSeverities = []string{"low", "medium", "high", "critical"}
+
+	// issueIDRegex splits T1.1.B2 or auth-system.B2 into the task ID and the
+	// issue number; the task ID is everything before the last .B<n>
+	issueIDRegex = regexp.MustCompile(`^([a-zA-Z0-9][a-zA-Z0-9._-]*)\.B([1-9][0-9]*)$`)
+)
+
+var taskIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Track bugs and change requests on tasks",
+	Long: `Task Issue Tracking
+
+Record bugs, enhancements and change requests against a task. Issue IDs are
+numbered after their task: T1.1.B1, T1.1.B2, ... or auth-system.B1 for a task
+without a T<phase>.<task> ID.
+
+Every change recalculates the task's progress section:
+  total_issues, resolved_issues, open_bugs
+
+Available Commands:
+  add       Add an issue to a task
+  update    Update an issue
+  list      List issues of a task, or query issues across all projects
+
+Examples:
+  dppm task issue add T1.1 --title "Fix mobile login" --type bug --component T1.1.2
+  dppm task issue update T1.1.B1 --status in_progress
+  dppm task issue list --type bug --status todo`,
+}
+
+var taskIssueAddCmd = &cobra.Command{
+	Use:   "add [task-id]",
+	Short: "Add an issue to a task",
+	Long: `Add a Task Issue
+
+Adds an issue to the task. The ID is generated as the next T1.1.BN and the
+reporter is the current author (see 'dppm task history --help').
+
+Types: bug, enhancement, change_request
+Severities: low, medium, high, critical
+Statuses: todo, in_progress, done, closed
+
+Examples:
+  dppm task issue add T1.1 --title "Login fails on mobile" --type bug --severity high
+  dppm task issue add T1.1 --title "Support SSO" --type enhancement --component T1.1.1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		title, _ := cmd.Flags().GetString("title")
+		issueType, _ := cmd.Flags().GetString("type")
+		severity, _ := cmd.Flags().GetString("severity")
+		status, _ := cmd.Flags().GetString("status")
+		component, _ := cmd.Flags().GetString("component")
+		assignedTo, _ := cmd.Flags().GetString("assigned-to")
+		description, _ := cmd.Flags().GetString("description")
+
+		if err := validateIssueFields(issueType, status, severity); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := ValidateDescription(title); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid title: %v\n", err)
+			os.Exit(1)
+		}
+
+		var added Issue
+		task, err := modifyTask(cmd, projectID, taskID, func(task *Task) error {
+			if component != "" && findComponent(*task, component) < 0 {
+				return fmt.Errorf("component '%s' not found in task '%s'", component, task.ID)
+			}
+
+			// Issue IDs must lead back to their task, see 'issue update'
+			id := nextIssueID(*task)
+			if !issueIDRegex.MatchString(id) {
+				return fmt.Errorf("cannot number issues of task '%s': its ID contains characters not allowed in issue IDs", task.ID)
+			}
+
+			now := nowTimestamp()
+			added = Issue{
+				ID:              id,
+				Title:           title,
+				Type:            issueType,
+				Status:          status,
+				Severity:        severity,
+				ParentComponent: component,
+				Description:     description,
+				ReportedBy:      currentAuthor(),
+				AssignedTo:      assignedTo,
+				Created:         now,
+				Updated:         now,
+			}
+			task.Issues = append(task.Issues, added)
+			recordChange(task, "issues."+id, "", title)
+			recalculateProgress(task)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Issue '%s' added to task '%s'\n", added.ID, task.ID)
+		printIssueProgress(task.Progress)
+		fmt.Printf("\n🎯 HØJRE HEGN - NEXT ACTIONS:\n")
+		fmt.Printf("  dppm task issue update %s --status in_progress  # Start fixing it\n", added.ID)
+		fmt.Printf("  dppm task issue list %s                         # See all issues\n", task.ID)
+	},
+}
+
+var taskIssueUpdateCmd = &cobra.Command{
+	Use:   "update [issue-id]",
+	Short: "Update an issue",
+	Long: `Update a Task Issue
+
+Updates the given properties of an issue and recalculates the task's
+progress. The task is taken from the issue ID (T1.1.B2 belongs to T1.1).
+
+Pass --if-revision N to refuse the update when the task holding the issue
+has changed since you read revision N.
+
+Examples:
+  dppm task issue update T1.1.B1 --status in_progress --assigned-to gemini
+  dppm task issue update T1.1.B1 --status done
+  dppm task issue update T2.3.B4 --severity critical --project web-app
+  dppm task issue update T1.1.B1 --status done --if-revision 5`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		issueType, _ := cmd.Flags().GetString("type")
+		severity, _ := cmd.Flags().GetString("severity")
+		status, _ := cmd.Flags().GetString("status")
+
+		if err := validateIssueFields(issueType, status, severity); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		matches := issueIDRegex.FindStringSubmatch(issueID)
+		if matches == nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid issue ID '%s' (expected format: <task-id>.B<n>, e.g. T1.1.B1)\n", issueID)
+			os.Exit(1)
+		}
+
+		task, err := modifyTask(cmd, projectID, matches[1], func(task *Task) error {
+			index := findIssue(*task, issueID)
+			if index < 0 {
+				return fmt.Errorf("issue '%s' not found in task '%s'", issueID, task.ID)
+			}
+			issue := &task.Issues[index]
+			field := "issues." + issue.ID
+
+			if cmd.Flags().Changed("status") {
+				recordChange(task, field+".status", issue.Status, status)
+				issue.Status = status
+			}
+			if cmd.Flags().Changed("type") {
+				recordChange(task, field+".type", issue.Type, issueType)
+				issue.Type = issueType
+			}
+			if cmd.Flags().Changed("severity") {
+				recordChange(task, field+".severity", issue.Severity, severity)
+				issue.Severity = severity
+			}
+			if cmd.Flags().Changed("component") {
+				component, _ := cmd.Flags().GetString("component")
+				if component != "" && findComponent(*task, component) < 0 {
+					return fmt.Errorf("component '%s' not found in task '%s'", component, task.ID)
+				}
+				recordChange(task, field+".parent_component", issue.ParentComponent, component)
+				issue.ParentComponent = component
+			}
+			if cmd.Flags().Changed("title") {
+				title, _ := cmd.Flags().GetString("title")
+				recordChange(task, field+".title", issue.Title, title)
+				issue.Title = title
+			}
+			if cmd.Flags().Changed("assigned-to") {
+				assignedTo, _ := cmd.Flags().GetString("assigned-to")
+				recordChange(task, field+".assigned_to", issue.AssignedTo, assignedTo)
+				issue.AssignedTo = assignedTo
+			}
+			if cmd.Flags().Changed("description") {
+				description, _ := cmd.Flags().GetString("description")
+				recordChange(task, field+".description", issue.Description, description)
+				issue.Description = description
+			}
+			issue.Updated = nowTimestamp()
+
+			recalculateProgress(task)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Issue '%s' of task '%s' updated\n", issueID, task.ID)
+		printIssueProgress(task.Progress)
+	},
+}
+
+var taskIssueListCmd = &cobra.Command{
+	Use:   "list [task-id]",
+	Short: "List issues of a task, or query issues across all projects",
+	Long: `List Task Issues
+
+With a task ID, lists the issues of that task. Without one, queries the
+issues of every task in --project, or in all projects.
+
+Filters accept comma-separated values:
+  --type       bug, enhancement, change_request
+  --status     todo, in_progress, done, closed (or 'open' for todo,in_progress)
+  --severity   low, medium, high, critical
+
+Examples:
+  dppm task issue list T1.1
+  dppm task issue list --type bug --status todo
+  dppm task issue list --type bug --severity high,critical --status open
+  dppm task issue list --project web-app --output json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		filter := issueFilter{
+			types:      splitFilter(cmd, "type"),
+			statuses:   splitFilter(cmd, "status"),
+			severities: splitFilter(cmd, "severity"),
+		}
+		if containsString(filter.statuses, "open") {
+			filter.statuses = append(removeString(filter.statuses, "open"), "todo", "in_progress")
+		}
+
+		var tasks []Task
+		if len(args) == 1 {
+			task, err := loadTaskForCommand(projectID, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Task '%s' not found\n", args[0])
+				os.Exit(1)
+			}
+			tasks = []Task{*task}
+		} else {
+			projectIDs := []string{projectID}
+			if projectID == "" {
+				var err error
+				if projectIDs, err = allProjectIDs(); err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			for _, id := range projectIDs {
+				projectTasks, err := loadProjectTasks(id)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+					os.Exit(1)
+				}
+				tasks = append(tasks, projectTasks...)
+			}
+		}
+
+		issues := []TaskIssue{}
+		for _, task := range tasks {
+			for _, issue := range task.Issues {
+				if filter.matches(issue) {
+					issues = append(issues, TaskIssue{ProjectID: task.ProjectID, TaskID: task.ID, Issue: issue})
+				}
+			}
+		}
+		if printStructured(issues) {
+			return
+		}
+
+		fmt.Println("Issues:")
+		fmt.Println("=======")
+
+		if len(issues) == 0 {
+			fmt.Println("No matching issues.")
+			return
+		}
+
+		for _, item := range issues {
+			fmt.Printf("%s %s - %s\n", issueIcon(item.Issue), item.ID, item.Title)
+			fmt.Printf("   Project: %s  Task: %s\n", item.ProjectID, item.TaskID)
+			details := fmt.Sprintf("   Type: %s  Status: %s", item.Type, item.Status)
+			if item.Severity != "" {
+				details += "  Severity: " + item.Severity
+			}
+			if item.AssignedTo != "" {
+				details += "  Assigned: " + item.AssignedTo
+			}
+			fmt.Println(details)
+			if item.ParentComponent != "" {
+				fmt.Printf("   Component: %s\n", item.ParentComponent)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("%d issue(s)\n", len(issues))
+	},
+}
+
+// TaskIssue is an issue together with the task it belongs to
+type TaskIssue struct {
+	ProjectID string `yaml:"project_id" json:"project_id"`
+	TaskID    string `yaml:"task_id" json:"task_id"`
+	Issue     `yaml:",inline"`
+}
+
+type issueFilter struct {
+	types      []string
+	statuses   []string
+	severities []string
+}
+
+func (f issueFilter) matches(issue Issue) bool {
+	if len(f.types) > 0 && !containsString(f.types, issue.Type) {
+		return false
+	}
+	if len(f.statuses) > 0 && !containsString(f.statuses, issue.Status) {
+		return false
+	}
+	if len(f.severities) > 0 && !containsString(f.severities, issue.Severity) {
+		return false
+	}
+	return true
+}
+
+// splitFilter reads a comma-separated flag value into a list
+func splitFilter(cmd *cobra.Command, name string) []string {
+	value, _ := cmd.Flags().GetString(name)
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// isIssueResolved reports whether an issue no longer counts as open
+func isIssueResolved(issue Issue) bool {
+	return issue.Status == "done" || issue.Status == "closed"
+}
+
+func validateIssueFields(issueType, status, severity string) error {
+	if issueType != "" && !containsString(validIssueTypes, issueType) {
+		return fmt.Errorf("invalid issue type '%s'. Must be one of: %s", issueType, strings.Join(validIssueTypes, ", "))
+	}
+	if status != "" && !containsString(validIssueStatuses, status) {
+		return fmt.Errorf("invalid issue status '%s'. Must be one of: %s", status, strings.Join(validIssueStatuses, ", "))
+	}
+	if severity != "" && !containsString(validIssueSeverities, severity) {
+		return fmt.Errorf("invalid severity '%s'. Must be one of: %s", severity, strings.Join(validIssueSeverities, ", "))
+	}
+	return nil
+}
+
+// nextIssueID numbers issues after their task: T1.1.B1, T1.1.B2, ...
+func nextIssueID(task Task) string {
+	highest := 0
+	for _, issue := range task.Issues {
+		matches := issueIDRegex.FindStringSubmatch(issue.ID)
+		if matches == nil || matches[1] != task.ID {
+			continue
+		}
+		if n, _ := strconv.Atoi(matches[2]); n > highest {
+			highest = n
+		}
+	}
+	return fmt.Sprintf("%s.B%d", task.ID, highest+1)
+}
+
+func findIssue(task Task, issueID string) int {
+	for i, issue := range task.Issues {
+		if issue.ID == issueID {
+			return i
+		}
+	}
+	return -1
+}
+
+func printIssueProgress(progress Progress) {
+	fmt.Printf("🐛 Issues: %d/%d resolved, %d open bug(s)\n", progress.ResolvedIssues, progress.TotalIssues, progress.OpenBugs)
+}
+
+func issueIcon(issue Issue) string {
+	switch {
+	case isIssueResolved(issue):
+		return "✅"
+	case issue.Status == "in_progress":
+		return "🔄"
+	case issue.Type == "bug":
+		return "🐛"
+	default:
+		return "📝"
+	}
+}
+
+func init() {
+	taskIssueAddCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskIssueAddCmd.Flags().StringP("title", "t", "", "Issue title (required)")
+	taskIssueAddCmd.Flags().String("type", "bug", "Issue type (bug, enhancement, change_request)")
+	taskIssueAddCmd.Flags().String("severity", "medium", "Issue severity (low, medium, high, critical)")
+	taskIssueAddCmd.Flags().String("status", "todo", "Issue status (todo, in_progress, done, closed)")
+	taskIssueAddCmd.Flags().String("component", "", "Component the issue belongs to")
+	taskIssueAddCmd.Flags().String("assigned-to", "", "Issue assignee")
+	taskIssueAddCmd.Flags().StringP("description", "d", "", "Issue description")
+	taskIssueAddCmd.MarkFlagRequired("title")
+
+	taskIssueUpdateCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskIssueUpdateCmd.Flags().StringP("title", "t", "", "Issue title")
+	taskIssueUpdateCmd.Flags().String("type", "", "Issue type (bug, enhancement, change_request)")
+	taskIssueUpdateCmd.Flags().String("severity", "", "Issue severity (low, medium, high, critical)")
+	taskIssueUpdateCmd.Flags().String("status", "", "Issue status (todo, in_progress, done, closed)")
+	taskIssueUpdateCmd.Flags().String("component", "", "Component the issue belongs to")
+	taskIssueUpdateCmd.Flags().String("assigned-to", "", "Issue assignee")
+	taskIssueUpdateCmd.Flags().StringP("description", "d", "", "Issue description")
+	taskIssueUpdateCmd.Flags().Int("if-revision", 0, "Only update if the task is still at this revision")
+
+	taskIssueListCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, queries all projects)")
+	taskIssueListCmd.Flags().String("type", "", "Filter by type (comma-separated)")
+	taskIssueListCmd.Flags().String("status", "", "Filter by status (comma-separated, 'open' = todo,in_progress)")
+	taskIssueListCmd.Flags().String("severity", "", "Filter by severity (comma-separated)")
+
+	taskIssueCmd.AddCommand(taskIssueAddCmd)
+	taskIssueCmd.AddCommand(taskIssueUpdateCmd)
+	taskIssueCmd.AddCommand(taskIssueListCmd)
+	taskCmd.AddCommand(taskIssueCmd)
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestNextIssueID(t *testing.T) {
+	tests := []struct {
+		task Task
+		want string
+	}{
+		{Task{ID: "T1.1"}, "T1.1.B1"},
+		{Task{ID: "T1.1", Issues: []Issue{{ID: "T1.1.B1"}, {ID: "T1.1.B3"}}}, "T1.1.B4"},
+		{Task{ID: "T1.1-login"}, "T1.1-login.B1"},
+		{Task{ID: "T2.10-api_v2", Issues: []Issue{{ID: "T2.10-api_v2.B2"}}}, "T2.10-api_v2.B3"},
+		{Task{ID: "auth-system", Issues: []Issue{{ID: "auth-system.B1"}}}, "auth-system.B2"},
+		{Task{ID: "T1.1.B1"}, "T1.1.B1.B1"},
+		// Issues kept from before a move still carry the old task ID
+		{Task{ID: "T2.1", Issues: []Issue{{ID: "T1.4.B7"}}}, "T2.1.B1"},
+	}
+
+	for _, test := range tests {
+		got := nextIssueID(test.task)
+		if got != test.want {
+			t.Errorf("nextIssueID(%s) = %s, want %s", test.task.ID, got, test.want)
+		}
+		if matches := issueIDRegex.FindStringSubmatch(got); matches == nil || matches[1] != test.task.ID {
+			t.Errorf("issue ID %s does not lead back to task %s", got, test.task.ID)
+		}
+	}
+}
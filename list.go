@@ -3,10 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var listCmd = &cobra.Command{
@@ -42,6 +40,10 @@ AI Usage:
   This command is designed to provide verbose, structured output that
   AI systems can easily parse and understand for project analysis,
   status reporting, and workflow automation.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		warnAboutConflicts(projectID)
+	},
 }
 
 var listProjectsCmd = &cobra.Command{
@@ -72,6 +74,7 @@ Output Format:
 Examples:
   dppm list projects              # Show all projects
   dppm list projects | grep web   # Filter projects containing 'web'
+  dppm list projects --output json # Machine-readable project list
 
 AI Integration:
   The output is designed to be easily parseable by AI systems for:
@@ -80,42 +83,115 @@ AI Integration:
   - Progress tracking
   - Automated reporting`,
 	Run: func(cmd *cobra.Command, args []string) {
-		projectsDir := filepath.Join(projectsPath, "projects")
-
-		entries, err := os.ReadDir(projectsDir)
+		projects, err := store.ListProjects()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading projects directory: %v\n", err)
 			return
 		}
+		if projects == nil {
+			projects = []Project{}
+		}
+		if printStructured(projects) {
+			return
+		}
 
 		fmt.Println("Projects:")
 		fmt.Println("=========")
 
-		for _, entry := range entries {
-			if entry.IsDir() {
-				projectFile := filepath.Join(projectsDir, entry.Name(), "project.yaml")
-
-				data, err := os.ReadFile(projectFile)
-				if err != nil {
-					continue
-				}
-
-				var project Project
-				if err := yaml.Unmarshal(data, &project); err != nil {
-					continue
-				}
-
-				fmt.Printf("ID: %s\n", project.ID)
-				fmt.Printf("Name: %s\n", project.Name)
-				fmt.Printf("Status: %s\n", project.Status)
-				fmt.Printf("Owner: %s\n", project.Owner)
-				fmt.Printf("Updated: %s\n", project.Updated)
-				fmt.Println("---")
-			}
+		for _, project := range projects {
+			fmt.Printf("ID: %s\n", project.ID)
+			fmt.Printf("Name: %s\n", project.Name)
+			fmt.Printf("Status: %s\n", project.Status)
+			fmt.Printf("Owner: %s\n", project.Owner)
+			fmt.Printf("Updated: %s\n", project.Updated)
+			fmt.Printf("Revision: %d\n", project.Revision)
+			fmt.Println("---")
+		}
+	},
+}
+
+var listProjectPhasesCmd = &cobra.Command{
+	Use:   "phases",
+	Short: "List phases of a project",
+	Long: `List Phases
+
+Same as 'dppm phase list': lists the phases of a project with their status
+and task metrics.
+
+Examples:
+  dppm list phases --project web-app
+  dppm list phases --project web-app --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listPhasesCmd.Run(cmd, args)
+	},
+}
+
+var listTasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List tasks of a project or phase",
+	Long: `List Tasks
+
+Display the tasks of a project, optionally limited to one phase and narrowed
+down with a filter expression. --filter uses the same syntax as 'dppm query';
+see 'dppm query --help' for the fields and operators.
+
+Examples:
+  dppm list tasks --project web-app
+  dppm list tasks --project web-app --phase P1
+  dppm list tasks --project web-app --filter 'status!=done AND priority>=high'
+  dppm list tasks --project web-app --filter 'label:api' --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		phaseID, _ := cmd.Flags().GetString("phase")
+		expr, _ := cmd.Flags().GetString("filter")
+
+		filter, err := parseTaskFilter(expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter: %v\n", err)
+			os.Exit(1)
 		}
+		exists, err := store.ProjectExists(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking project existence: %v\n", err)
+			os.Exit(1)
+		}
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' does not exist\n", projectID)
+			os.Exit(1)
+		}
+
+		tasks, err := queryTasks([]string{projectID}, func(task Task) bool {
+			return (phaseID == "" || task.PhaseID == phaseID) && filter(task)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(tasks) {
+			return
+		}
+
+		if phaseID != "" {
+			fmt.Printf("Tasks in %s / %s:\n", projectID, phaseID)
+		} else {
+			fmt.Printf("Tasks in %s:\n", projectID)
+		}
+		fmt.Println("=================")
+		printTaskTable(tasks)
 	},
 }
 
 func init() {
+	listProjectPhasesCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	listProjectPhasesCmd.MarkFlagRequired("project")
+
+	listTasksCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	listTasksCmd.Flags().String("phase", "", "Only list tasks of this phase")
+	listTasksCmd.Flags().StringP("filter", "f", "", "Filter expression (see 'dppm query --help')")
+	listTasksCmd.MarkFlagRequired("project")
+
 	listCmd.AddCommand(listProjectsCmd)
+	listCmd.AddCommand(listProjectPhasesCmd)
+	listCmd.AddCommand(listTasksCmd)
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockTimeout is how long an invocation waits for another one to finish
+	lockTimeout = 10 * time.Second
+	// lockRetryInterval is the polling interval while waiting for a lock
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// getLockDir returns the machine-local directory holding project lock files.
+// Locks live outside Dropbox so they never sync to (or conflict on) other machines.
+func getLockDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	lockDir := filepath.Join(home, ".dppm", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return "", err
+	}
+	return lockDir, nil
+}
+
+// acquireFileLock takes an exclusive OS lock (flock, LockFileEx) on lockFile.
+// The operating system drops the lock when its holder exits, even after a
+// crash, so a lock left behind by a dead process is free again and a running
+// holder is never broken. The file records the owner's PID and start time for
+// error messages only. The returned function releases the lock.
+//
+// Lock files are never removed: a waiter may already hold the old file open,
+// and deleting it would let that waiter and a newcomer lock different files.
+func acquireFileLock(lockFile string) (func(), error) {
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %v", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %v", filepath.Base(lockFile), err)
+		}
+		if locked {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			f.Close()
+			owner := "unknown process"
+			if pid, _, err := readLockFile(lockFile); err == nil {
+				owner = fmt.Sprintf("process %d", pid)
+			}
+			return nil, fmt.Errorf("timed out waiting for lock %s held by %s", filepath.Base(lockFile), owner)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	if err := f.Truncate(0); err == nil {
+		fmt.Fprintf(f, "%d\n%s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+func readLockFile(lockFile string) (int, time.Time, error) {
+	data, err := os.ReadFile(lockFile)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		return 0, time.Time{}, fmt.Errorf("malformed lock file")
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("malformed lock file: %v", err)
+	}
+	acquired, err := time.Parse(time.RFC3339, strings.TrimSpace(lines[1]))
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("malformed lock file: %v", err)
+	}
+
+	return pid, acquired, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteYAMLFileLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "T1.1.yaml")
+
+	for _, title := range []string{"First", "Second"} {
+		if err := writeYAMLFile(path, &Task{ID: "T1.1", Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var task Task
+	if err := readYAMLFile(path, &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "Second" {
+		t.Errorf("title = %q, want the last write", task.Title)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("folder holds %v, want only T1.1.yaml", names)
+	}
+}
+
+func TestProjectLockSerializesUpdates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := NewYAMLStore(t.TempDir())
+	if err := s.SaveTask(&Task{ID: "T1.1", ProjectID: "demo", PhaseID: "P1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every writer does a read-modify-write; without the lock updates get lost
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := s.Lock("demo")
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer unlock()
+
+			task, err := s.LoadTask("demo", "T1.1")
+			if err != nil {
+				errs <- err
+				return
+			}
+			task.StoryPoints++
+			errs <- s.SaveTask(task)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	task, err := s.LoadTask("demo", "T1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.StoryPoints != writers {
+		t.Errorf("story points = %d after %d locked increments", task.StoryPoints, writers)
+	}
+}
@@ -51,6 +51,7 @@ Examples:
   dppm task create auth --project web-app --phase backend --title "Authentication"
   dppm status project web-app
   dppm list projects
+  dppm status project web-app --output json
   dppm collab find docs/                # Find AI collaboration tasks
   dppm collab wiki "task handoff"       # Learn collaboration patterns
 
@@ -78,6 +79,10 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(wikiCmd)
 	rootCmd.AddCommand(collabCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.AddCommand(queryCmd)
 
 	// Add --wiki flag for direct search
 	rootCmd.Flags().String("wiki", "", "Search DPPM knowledge base (e.g. --wiki \"create task\")")
@@ -85,6 +90,9 @@ func init() {
 	// Add version and setup flags
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
 	rootCmd.Flags().Bool("setup", false, "Run first-time setup guide (REQUIRED on fresh install)")
+
+	// Machine-readable output for list, show and status commands
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "Output format for list, show and status commands (text, json, yaml)")
 }
 
 func main() {
@@ -114,6 +122,13 @@ func main() {
 		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 		os.Exit(1)
 	}
+	yamlStore := NewYAMLStore(projectsPath)
+	store = yamlStore
+	if os.Getenv("DPPM_NO_INDEX") == "" {
+		if indexPath, err := getIndexPath(); err == nil {
+			store = NewIndexedStore(yamlStore, indexPath)
+		}
+	}
 
 	// Database functionality temporarily disabled to avoid CGO dependency
 	// TODO: Consider alternative storage if ERD features needed
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MemoryStore keeps projects, phases and tasks in memory. It is meant for tests
+// and dry runs; nothing is persisted when the process exits.
+type MemoryStore struct {
+	mu       sync.Mutex
+	projects map[string]Project
+	phases   map[string]map[string]Phase
+	tasks    map[string]map[string]Task
+	locks    map[string]*sync.Mutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		projects: make(map[string]Project),
+		phases:   make(map[string]map[string]Phase),
+		tasks:    make(map[string]map[string]Task),
+		locks:    make(map[string]*sync.Mutex),
+	}
+}
+
+func (s *MemoryStore) ListProjects() ([]Project, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var projects []Project
+	for _, project := range s.projects {
+		var copied Project
+		if err := cloneEntity(project, &copied); err != nil {
+			return nil, err
+		}
+		projects = append(projects, copied)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+	return projects, nil
+}
+
+func (s *MemoryStore) ProjectExists(projectID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.projects[projectID]
+	return exists, nil
+}
+
+func (s *MemoryStore) LoadProject(projectID string) (*Project, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, exists := s.projects[projectID]
+	if !exists {
+		return nil, notFoundError("project", projectID)
+	}
+	var copied Project
+	if err := cloneEntity(project, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveProject(project *Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.projects[project.ID]; exists && existing.Revision != project.Revision {
+		return revisionConflictError("project", project.ID, existing.Revision, project.Revision)
+	}
+
+	var copied Project
+	if err := cloneEntity(*project, &copied); err != nil {
+		return err
+	}
+	copied.Revision++
+	project.Revision++
+	s.projects[project.ID] = copied
+	return nil
+}
+
+func (s *MemoryStore) ListPhases(projectID string) ([]Phase, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var phases []Phase
+	for _, phase := range s.phases[projectID] {
+		var copied Phase
+		if err := cloneEntity(phase, &copied); err != nil {
+			return nil, err
+		}
+		phases = append(phases, copied)
+	}
+	sort.Slice(phases, func(i, j int) bool { return phases[i].ID < phases[j].ID })
+	return phases, nil
+}
+
+func (s *MemoryStore) PhaseExists(projectID, phaseID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.phases[projectID][phaseID]
+	return exists, nil
+}
+
+func (s *MemoryStore) LoadPhase(projectID, phaseID string) (*Phase, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	phase, exists := s.phases[projectID][phaseID]
+	if !exists {
+		return nil, notFoundError("phase", phaseID)
+	}
+	var copied Phase
+	if err := cloneEntity(phase, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+func (s *MemoryStore) SavePhase(phase *Phase) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.phases[phase.ProjectID] == nil {
+		s.phases[phase.ProjectID] = make(map[string]Phase)
+	}
+	if existing, exists := s.phases[phase.ProjectID][phase.ID]; exists && existing.Revision != phase.Revision {
+		return revisionConflictError("phase", phase.ID, existing.Revision, phase.Revision)
+	}
+
+	var copied Phase
+	if err := cloneEntity(*phase, &copied); err != nil {
+		return err
+	}
+	copied.Revision++
+	phase.Revision++
+	s.phases[phase.ProjectID][phase.ID] = copied
+	return nil
+}
+
+func (s *MemoryStore) ListTasks(projectID string) ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []Task
+	for _, task := range s.tasks[projectID] {
+		var copied Task
+		if err := cloneEntity(task, &copied); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, copied)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+func (s *MemoryStore) LoadTask(projectID, taskID string) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, exists := s.tasks[projectID][taskID]
+	if !exists {
+		for _, candidate := range s.tasks[projectID] {
+			if containsString(candidate.Aliases, taskID) {
+				task, exists = candidate, true
+				break
+			}
+		}
+	}
+	if !exists {
+		return nil, notFoundError("task", taskID)
+	}
+	var copied Task
+	if err := cloneEntity(task, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveTask(task *Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tasks[task.ProjectID] == nil {
+		s.tasks[task.ProjectID] = make(map[string]Task)
+	}
+	if existing, exists := s.tasks[task.ProjectID][task.ID]; exists && existing.Revision != task.Revision {
+		return revisionConflictError("task", task.ID, existing.Revision, task.Revision)
+	}
+
+	var copied Task
+	if err := cloneEntity(*task, &copied); err != nil {
+		return err
+	}
+	copied.Revision++
+	task.Revision++
+	s.tasks[task.ProjectID][task.ID] = copied
+	return nil
+}
+
+func (s *MemoryStore) DeleteTask(projectID, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tasks[projectID][taskID]; !exists {
+		return notFoundError("task", taskID)
+	}
+	delete(s.tasks[projectID], taskID)
+	return nil
+}
+
+func (s *MemoryStore) MoveTask(oldID string, task *Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.tasks[task.ProjectID][oldID]
+	if !exists {
+		return notFoundError("task", oldID)
+	}
+	if existing.Revision != task.Revision {
+		return revisionConflictError("task", oldID, existing.Revision, task.Revision)
+	}
+	if _, taken := s.tasks[task.ProjectID][task.ID]; taken && oldID != task.ID {
+		return fmt.Errorf("task '%s' already exists in project '%s'", task.ID, task.ProjectID)
+	}
+
+	var copied Task
+	if err := cloneEntity(*task, &copied); err != nil {
+		return err
+	}
+	copied.Revision++
+	task.Revision++
+	delete(s.tasks[task.ProjectID], oldID)
+	s.tasks[task.ProjectID][task.ID] = copied
+	return nil
+}
+
+func (s *MemoryStore) Lock(projectID string) (func(), error) {
+	s.mu.Lock()
+	lock, exists := s.locks[projectID]
+	if !exists {
+		lock = &sync.Mutex{}
+		s.locks[projectID] = lock
+	}
+	s.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock, nil
+}
+
+// cloneEntity deep-copies src into dst through its YAML form, so callers never
+// share slices or maps with the stored value
+func cloneEntity(src, dst interface{}) error {
+	data, err := yaml.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, dst)
+}
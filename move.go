@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var taskMoveCmd = &cobra.Command{
+	Use:   "move [task-id]",
+	Short: "Move a task to another phase",
+	Long: `Move a Task to Another Phase
+
+Task IDs carry their phase number (T2.* tasks live in P2), so moving a task
+gives it the next free ID in the target phase. A suffix is kept: T1.3-auth
+moved to P2 becomes e.g. T2.4-auth.
+
+Subtasks move along and are renumbered under the new ID: T1.3.1 of T1.3
+becomes T2.4.1.
+
+Everything pointing at the old ID is rewritten across the project:
+  • dependency_ids, blocked_by and blocking of other tasks
+  • the tasks list of the phases
+  • the IDs of the task's own components and issues
+
+The old ID is kept in the task's aliases, so 'dppm task show T1.3' and
+other lookups still find the task.
+
+Examples:
+  dppm task move T1.3 --to-phase P2
+  dppm task move T1.3-auth --to-phase P2 --project web-app`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		toPhaseID, _ := cmd.Flags().GetString("to-phase")
+
+		if err := ValidatePhaseID(toPhaseID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		task, err := loadTaskForCommand(projectID, taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
+			os.Exit(1)
+		}
+
+		unlock, err := store.Lock(task.ProjectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		fromPhaseID := task.PhaseID
+		moved, subtasks, rewritten, err := moveTaskToPhase(task.ProjectID, task.ID, toPhaseID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if moved.ID != task.ID {
+			fmt.Printf("✅ Task '%s' moved from %s to %s as '%s'\n", task.ID, fromPhaseID, toPhaseID, moved.ID)
+			fmt.Printf("   '%s' is kept as an alias\n", task.ID)
+		} else {
+			fmt.Printf("✅ Task '%s' moved from %s to %s\n", task.ID, fromPhaseID, toPhaseID)
+		}
+		if len(subtasks) > 0 {
+			var ids []string
+			for _, subtask := range subtasks {
+				ids = append(ids, subtask.ID)
+			}
+			fmt.Printf("📦 Subtasks moved along: %s\n", strings.Join(ids, ", "))
+		}
+		if len(rewritten) > 0 {
+			fmt.Printf("🔗 Updated references in: %s\n", strings.Join(rewritten, ", "))
+		}
+	},
+}
+
+// moveTaskToPhase moves a task into another phase of the same project,
+// renumbering it when its ID belongs to a different phase number and rewriting
+// references to it. Subtasks (T1.3.1 of T1.3) move along and are renumbered
+// under the new ID. It returns the moved task, its moved subtasks and the IDs
+// of the other tasks that were updated. The caller must hold the project lock.
+func moveTaskToPhase(projectID, taskID, toPhaseID string) (*Task, []Task, []string, error) {
+	task, err := store.LoadTask(projectID, taskID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil, nil, fmt.Errorf("task '%s' not found in project '%s'", taskID, projectID)
+		}
+		return nil, nil, nil, err
+	}
+	if task.PhaseID == toPhaseID {
+		return nil, nil, nil, fmt.Errorf("task '%s' is already in phase '%s'", task.ID, toPhaseID)
+	}
+
+	target, err := store.LoadPhase(projectID, toPhaseID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("phase '%s' not found in project '%s'", toPhaseID, projectID)
+	}
+	if target.Status == "completed" || target.Status == "cancelled" {
+		return nil, nil, nil, fmt.Errorf("cannot move tasks to phase '%s' (%s)", toPhaseID, target.Status)
+	}
+
+	tasks, err := store.ListTasks(projectID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	oldID := task.ID
+	newID := oldID
+	if ValidateTaskID(oldID, toPhaseID) != nil {
+		if newID, err = nextTaskID(tasks, toPhaseID, taskIDSuffix(oldID)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// Every moved task with its old ID, the parent first
+	renames := map[string]string{oldID: newID}
+	moving := []*Task{task}
+	oldIDs := []string{oldID}
+	for _, other := range subtasksOf(tasks, oldID) {
+		other := other
+		renames[other.ID] = subtaskID(other.ID, newID)
+		moving = append(moving, &other)
+		oldIDs = append(oldIDs, other.ID)
+	}
+
+	for i, moved := range moving {
+		recordChange(moved, "phase_id", moved.PhaseID, toPhaseID)
+		moved.PhaseID = toPhaseID
+		for from, to := range renames {
+			if from != to {
+				rewriteTaskReferences(moved, from, to)
+			}
+		}
+		if to := renames[oldIDs[i]]; to != oldIDs[i] {
+			renameTask(moved, to)
+		}
+		moved.Updated = nowTimestamp()
+
+		if err := store.MoveTask(oldIDs[i], moved); err != nil {
+			if i == 0 {
+				return nil, nil, nil, fmt.Errorf("failed to move task '%s': %v", oldIDs[i], err)
+			}
+			return task, nil, nil, fmt.Errorf("task moved, but subtask '%s' could not be moved: %v", oldIDs[i], err)
+		}
+	}
+	var subtasks []Task
+	for _, moved := range moving[1:] {
+		subtasks = append(subtasks, *moved)
+	}
+
+	var rewritten []string
+	for _, other := range tasks {
+		if _, moved := renames[other.ID]; moved {
+			continue
+		}
+		changed := false
+		for from, to := range renames {
+			if from != to && rewriteTaskReferences(&other, from, to) {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		other.Updated = nowTimestamp()
+		if err := store.SaveTask(&other); err != nil {
+			return task, subtasks, rewritten, fmt.Errorf("task moved, but references in '%s' could not be updated: %v", other.ID, err)
+		}
+		rewritten = append(rewritten, other.ID)
+	}
+
+	for _, from := range oldIDs {
+		if err := moveInPhaseTaskLists(projectID, from, renames[from], toPhaseID); err != nil {
+			return task, subtasks, rewritten, fmt.Errorf("task moved, but phase task lists could not be updated: %v", err)
+		}
+	}
+	return task, subtasks, rewritten, nil
+}
+
+// subtasksOf returns the subtasks of a task: T1.3.1 and T1.3.2-ui for T1.3 or
+// T1.3-auth. Tasks without a T<phase>.<task> ID have none.
+func subtasksOf(tasks []Task, parentID string) []Task {
+	parent := taskIDPartsRegex.FindStringSubmatch(parentID)
+	if parent == nil || parent[3] != "" {
+		return nil
+	}
+
+	var subtasks []Task
+	for _, task := range tasks {
+		matches := taskIDPartsRegex.FindStringSubmatch(task.ID)
+		if matches != nil && matches[3] != "" && matches[1] == parent[1] && matches[2] == parent[2] {
+			subtasks = append(subtasks, task)
+		}
+	}
+	return subtasks
+}
+
+// subtaskID renumbers a subtask under the new ID of its parent
+// (T1.3.1-ui under T2.6-auth becomes T2.6.1-ui)
+func subtaskID(id, parentID string) string {
+	matches := taskIDPartsRegex.FindStringSubmatch(id)
+	parent := taskIDPartsRegex.FindStringSubmatch(parentID)
+	if matches == nil || parent == nil {
+		return id
+	}
+	return fmt.Sprintf("T%s.%s%s%s", parent[1], parent[2], matches[3], matches[4])
+}
+
+// renameTask gives a task a new ID, carrying its component and issue IDs over
+// and remembering the old ID as an alias
+func renameTask(task *Task, newID string) {
+	oldID := task.ID
+	recordChange(task, "id", oldID, newID)
+	task.ID = newID
+	task.Aliases = addString(task.Aliases, oldID)
+
+	for i := range task.Components {
+		task.Components[i].ID = renamePrefixed(task.Components[i].ID, oldID, newID)
+	}
+	for i := range task.Issues {
+		task.Issues[i].ID = renamePrefixed(task.Issues[i].ID, oldID, newID)
+		task.Issues[i].ParentComponent = renamePrefixed(task.Issues[i].ParentComponent, oldID, newID)
+	}
+}
+
+// renamePrefixed swaps the task ID prefix of a component or issue ID
+func renamePrefixed(id, oldID, newID string) string {
+	if strings.HasPrefix(id, oldID+".") {
+		return newID + strings.TrimPrefix(id, oldID)
+	}
+	return id
+}
+
+// rewriteTaskReferences replaces oldID by newID in the dependency fields of a
+// task and reports whether anything changed
+func rewriteTaskReferences(task *Task, oldID, newID string) bool {
+	changed := false
+	for _, field := range []struct {
+		name string
+		ids  *[]string
+	}{
+		{"dependency_ids", &task.DependencyIDs},
+		{"blocked_by", &task.BlockedBy},
+		{"blocking", &task.Blocking},
+	} {
+		if !containsString(*field.ids, oldID) {
+			continue
+		}
+		before := strings.Join(*field.ids, ", ")
+		for i, id := range *field.ids {
+			if id == oldID {
+				(*field.ids)[i] = newID
+			}
+		}
+		recordChange(task, field.name, before, strings.Join(*field.ids, ", "))
+		changed = true
+	}
+	return changed
+}
+
+// moveInPhaseTaskLists removes oldID from the tasks list of every phase and,
+// if any phase listed it, adds newID to the target phase
+func moveInPhaseTaskLists(projectID, oldID, newID, toPhaseID string) error {
+	phases, err := store.ListPhases(projectID)
+	if err != nil {
+		return err
+	}
+
+	listed := false
+	for _, phase := range phases {
+		if containsString(phase.Tasks, oldID) {
+			listed = true
+		}
+	}
+	if !listed {
+		return nil
+	}
+
+	for _, phase := range phases {
+		before := len(phase.Tasks)
+		phase.Tasks = removeString(phase.Tasks, oldID)
+		changed := len(phase.Tasks) != before
+		if phase.ID == toPhaseID && !containsString(phase.Tasks, newID) {
+			phase.Tasks = append(phase.Tasks, newID)
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		phase.Updated = nowTimestamp()
+		if err := store.SavePhase(&phase); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	taskMoveCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskMoveCmd.Flags().String("to-phase", "", "Phase to move the task to (required)")
+	taskMoveCmd.MarkFlagRequired("to-phase")
+
+	taskCmd.AddCommand(taskMoveCmd)
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newMoveTestStore sets up project demo with P1 (T1.1, T1.2, T1.3-auth and
+// setup) and P2 (T2.1, formerly T2.5) in a MemoryStore
+func newMoveTestStore(t *testing.T) {
+	t.Helper()
+	store = NewMemoryStore()
+	for _, phase := range []Phase{
+		{ID: "P1", ProjectID: "demo", Tasks: []string{"T1.1", "T1.2", "T1.3-auth"}},
+		{ID: "P2", ProjectID: "demo", Tasks: []string{"T2.1"}},
+		{ID: "P3", ProjectID: "demo", Status: "completed"},
+	} {
+		phase := phase
+		if err := store.SavePhase(&phase); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, task := range []Task{
+		{ID: "T1.1", PhaseID: "P1", Blocking: []string{"T1.3-auth"}},
+		{ID: "T1.2", PhaseID: "P1", DependencyIDs: []string{"T1.3-auth"}, BlockedBy: []string{"T1.3-auth"}},
+		{
+			ID: "T1.3-auth", PhaseID: "P1", DependencyIDs: []string{"T1.1"},
+			Components: []Component{{ID: "T1.3-auth.1", Title: "Form"}},
+			Issues:     []Issue{{ID: "T1.3-auth.B1", ParentComponent: "T1.3-auth.1"}},
+		},
+		{ID: "setup", PhaseID: "P1"},
+		{ID: "T2.1", PhaseID: "P2", Aliases: []string{"T2.5"}},
+	} {
+		task := task
+		task.ProjectID = "demo"
+		if err := store.SaveTask(&task); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMoveTaskToPhaseRenumbers(t *testing.T) {
+	newMoveTestStore(t)
+
+	moved, _, rewritten, err := moveTaskToPhase("demo", "T1.3-auth", "P2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// T2.5 is a former ID, so the next free number is 6; the suffix is kept
+	if moved.ID != "T2.6-auth" || moved.PhaseID != "P2" {
+		t.Fatalf("moved to %s in %s, want T2.6-auth in P2", moved.ID, moved.PhaseID)
+	}
+	sort.Strings(rewritten)
+	if !reflect.DeepEqual(rewritten, []string{"T1.1", "T1.2"}) {
+		t.Errorf("rewritten references in %v", rewritten)
+	}
+
+	stored, err := store.LoadTask("demo", "T1.3-auth")
+	if err != nil {
+		t.Fatalf("old ID no longer resolves: %v", err)
+	}
+	if stored.ID != "T2.6-auth" || !reflect.DeepEqual(stored.Aliases, []string{"T1.3-auth"}) {
+		t.Errorf("lookup by old ID found %s with aliases %v", stored.ID, stored.Aliases)
+	}
+	if stored.Components[0].ID != "T2.6-auth.1" || stored.Issues[0].ID != "T2.6-auth.B1" || stored.Issues[0].ParentComponent != "T2.6-auth.1" {
+		t.Errorf("components %+v, issues %+v not renamed", stored.Components, stored.Issues)
+	}
+	var fields []string
+	for _, entry := range stored.History {
+		fields = append(fields, entry.Field+":"+entry.From+"→"+entry.To)
+	}
+	if !reflect.DeepEqual(fields, []string{"phase_id:P1→P2", "id:T1.3-auth→T2.6-auth"}) {
+		t.Errorf("history = %v", fields)
+	}
+
+	dependency, _ := store.LoadTask("demo", "T1.1")
+	dependent, _ := store.LoadTask("demo", "T1.2")
+	if !reflect.DeepEqual(dependency.Blocking, []string{"T2.6-auth"}) {
+		t.Errorf("T1.1 blocking = %v", dependency.Blocking)
+	}
+	if !reflect.DeepEqual(dependent.DependencyIDs, []string{"T2.6-auth"}) || !reflect.DeepEqual(dependent.BlockedBy, []string{"T2.6-auth"}) {
+		t.Errorf("T1.2 depends on %v, blocked by %v", dependent.DependencyIDs, dependent.BlockedBy)
+	}
+
+	p1, _ := store.LoadPhase("demo", "P1")
+	p2, _ := store.LoadPhase("demo", "P2")
+	if !reflect.DeepEqual(p1.Tasks, []string{"T1.1", "T1.2"}) || !reflect.DeepEqual(p2.Tasks, []string{"T2.1", "T2.6-auth"}) {
+		t.Errorf("phase task lists P1 %v, P2 %v", p1.Tasks, p2.Tasks)
+	}
+
+	// The next move into P2 picks the number after the moved task
+	moved, _, _, err = moveTaskToPhase("demo", "T1.1", "P2")
+	if err != nil || moved.ID != "T2.7" {
+		t.Errorf("second move: %v, %v", moved, err)
+	}
+}
+
+func TestMoveTaskToPhaseNumbersFreeFormIDs(t *testing.T) {
+	newMoveTestStore(t)
+
+	moved, _, rewritten, err := moveTaskToPhase("demo", "setup", "P2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.ID != "T2.6" || !reflect.DeepEqual(moved.Aliases, []string{"setup"}) || len(rewritten) != 0 {
+		t.Errorf("moved %s with aliases %v, rewritten %v", moved.ID, moved.Aliases, rewritten)
+	}
+	p2, _ := store.LoadPhase("demo", "P2")
+	if !reflect.DeepEqual(p2.Tasks, []string{"T2.1"}) {
+		t.Errorf("P2 lists %v although no phase listed setup before", p2.Tasks)
+	}
+}
+
+func TestMoveTaskToPhaseErrors(t *testing.T) {
+	newMoveTestStore(t)
+
+	tests := []struct {
+		taskID, toPhaseID string
+		message           string
+	}{
+		{"T1.1", "P1", "already in phase 'P1'"},
+		{"T1.1", "P3", "cannot move tasks to phase 'P3' (completed)"},
+		{"T1.1", "P9", "phase 'P9' not found"},
+		{"T1.9", "P2", "task 'T1.9' not found"},
+	}
+	for _, test := range tests {
+		_, _, _, err := moveTaskToPhase("demo", test.taskID, test.toPhaseID)
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("move %s to %s: error %v, want %q", test.taskID, test.toPhaseID, err, test.message)
+		}
+	}
+}
+
+func TestRewriteTaskReferences(t *testing.T) {
+	task := Task{ID: "T1.5", DependencyIDs: []string{"T1.1", "T1.3"}, Blocking: []string{"T1.3"}}
+	if !rewriteTaskReferences(&task, "T1.3", "T2.1") {
+		t.Fatal("no change reported")
+	}
+	if !reflect.DeepEqual(task.DependencyIDs, []string{"T1.1", "T2.1"}) || !reflect.DeepEqual(task.Blocking, []string{"T2.1"}) {
+		t.Errorf("dependency_ids %v, blocking %v", task.DependencyIDs, task.Blocking)
+	}
+	if len(task.History) != 2 {
+		t.Errorf("%d history entries, want one per changed field", len(task.History))
+	}
+	if rewriteTaskReferences(&task, "T1.9", "T2.2") {
+		t.Error("change reported for an ID the task does not reference")
+	}
+}
+
+func TestMoveTaskToPhaseMovesSubtasks(t *testing.T) {
+	store = NewMemoryStore()
+	for _, phase := range []Phase{
+		{ID: "P1", ProjectID: "demo", Tasks: []string{"T1.3", "T1.3.1", "T1.4"}},
+		{ID: "P2", ProjectID: "demo"},
+	} {
+		phase := phase
+		if err := store.SavePhase(&phase); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, task := range []Task{
+		{ID: "T1.3", PhaseID: "P1", Blocking: []string{"T1.3.1"}},
+		{ID: "T1.3.1", PhaseID: "P1", DependencyIDs: []string{"T1.3"}, Blocking: []string{"T1.4"}},
+		{ID: "T1.3.2-ui", PhaseID: "P1"},
+		{ID: "T1.4", PhaseID: "P1", DependencyIDs: []string{"T1.3.1"}},
+		{ID: "T1.30", PhaseID: "P1"},
+	} {
+		task := task
+		task.ProjectID = "demo"
+		if err := store.SaveTask(&task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, subtasks, rewritten, err := moveTaskToPhase("demo", "T1.3", "P2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subtaskIDs []string
+	for _, subtask := range subtasks {
+		subtaskIDs = append(subtaskIDs, subtask.ID)
+	}
+	sort.Strings(subtaskIDs)
+	if moved.ID != "T2.1" || !reflect.DeepEqual(subtaskIDs, []string{"T2.1.1", "T2.1.2-ui"}) {
+		t.Fatalf("moved %s with subtasks %v", moved.ID, subtaskIDs)
+	}
+	if !reflect.DeepEqual(rewritten, []string{"T1.4"}) {
+		t.Errorf("rewritten references in %v", rewritten)
+	}
+
+	subtask, err := store.LoadTask("demo", "T1.3.1")
+	if err != nil {
+		t.Fatalf("old subtask ID no longer resolves: %v", err)
+	}
+	if subtask.ID != "T2.1.1" || subtask.PhaseID != "P2" || !reflect.DeepEqual(subtask.Aliases, []string{"T1.3.1"}) {
+		t.Errorf("subtask is %s in %s with aliases %v", subtask.ID, subtask.PhaseID, subtask.Aliases)
+	}
+	if !reflect.DeepEqual(subtask.DependencyIDs, []string{"T2.1"}) {
+		t.Errorf("subtask depends on %v, want its renamed parent", subtask.DependencyIDs)
+	}
+	parent, _ := store.LoadTask("demo", "T2.1")
+	if !reflect.DeepEqual(parent.Blocking, []string{"T2.1.1"}) {
+		t.Errorf("parent blocking %v", parent.Blocking)
+	}
+	dependent, _ := store.LoadTask("demo", "T1.4")
+	if !reflect.DeepEqual(dependent.DependencyIDs, []string{"T2.1.1"}) {
+		t.Errorf("T1.4 depends on %v", dependent.DependencyIDs)
+	}
+	if unrelated, err := store.LoadTask("demo", "T1.30"); err != nil || unrelated.PhaseID != "P1" {
+		t.Errorf("T1.30 is not a subtask of T1.3 but was moved: %+v, %v", unrelated, err)
+	}
+
+	p1, _ := store.LoadPhase("demo", "P1")
+	p2, _ := store.LoadPhase("demo", "P2")
+	if !reflect.DeepEqual(p1.Tasks, []string{"T1.4"}) || !reflect.DeepEqual(p2.Tasks, []string{"T2.1", "T2.1.1"}) {
+		t.Errorf("phase task lists P1 %v, P2 %v", p1.Tasks, p2.Tasks)
+	}
+}
+
+func TestSubtaskID(t *testing.T) {
+	tests := []struct{ id, parentID, want string }{
+		{"T1.3.1", "T2.6", "T2.6.1"},
+		{"T1.3.2-ui", "T2.6-auth", "T2.6.2-ui"},
+		{"T1.3.B1", "T2.6", "T2.6.B1"},
+	}
+	for _, test := range tests {
+		if got := subtaskID(test.id, test.parentID); got != test.want {
+			t.Errorf("subtaskID(%s, %s) = %s, want %s", test.id, test.parentID, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var statusOrderCmd = &cobra.Command{
+	Use:   "order",
+	Short: "Show tasks in dependency order with the critical path",
+	Long: `Show Dependency Order
+
+Sorts all tasks of a project topologically by their dependencies and groups
+them into waves. Every task in a wave only depends on tasks in earlier waves,
+so the tasks of one wave can be worked on in parallel. Dependencies name
+tasks by ID, so the order is refused while two phases hold the same task ID.
+
+The critical path is the longest chain of remaining (not done) work through
+the dependency graph. It decides the earliest possible completion of the
+project: delays on critical tasks delay everything.
+
+Weights (--weight):
+  auto      Estimated hours if any task has them, else story points (default)
+  hours     time_tracking.estimated_hours
+  points    story_points
+  tasks     Every task counts as 1
+
+Tasks without an estimate count as 1. Done tasks count as 0.
+
+Examples:
+  dppm status order --project web-app
+  dppm status order --project web-app --weight points
+  dppm status order --project web-app --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		weight, _ := cmd.Flags().GetString("weight")
+
+		if projectID == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project is required")
+			os.Exit(1)
+		}
+
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+
+		waves, err := dependencyWaves(tasks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		unit, err := resolveWeightUnit(weight, tasks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		path, total := criticalPath(tasks, waves, unit)
+		if structuredOutput() {
+			report := OrderReport{
+				ProjectID:    projectID,
+				Waves:        waves,
+				CriticalPath: CriticalPathReport{Unit: unit, Tasks: path, Length: total},
+			}
+			if report.Waves == nil {
+				report.Waves = [][]string{}
+			}
+			if report.CriticalPath.Tasks == nil {
+				report.CriticalPath.Tasks = []string{}
+			}
+			printStructured(report)
+			return
+		}
+
+		fmt.Printf("Dependency Order for %s:\n", projectID)
+		fmt.Println("============================")
+
+		if len(tasks) == 0 {
+			fmt.Println("No tasks found.")
+			return
+		}
+
+		taskMap := buildTaskMap(tasks)
+		for i, wave := range waves {
+			fmt.Printf("\nWave %d", i+1)
+			if i == 0 {
+				fmt.Printf(" (no dependencies)")
+			}
+			fmt.Println(":")
+			for _, id := range wave {
+				task := taskMap[id]
+				line := fmt.Sprintf("  %s %s - %s (%s)", taskStateIcon(task, tasks), task.ID, task.Title, task.Status)
+				if len(task.DependencyIDs) > 0 {
+					line += " → depends on: " + strings.Join(task.DependencyIDs, ", ")
+				}
+				fmt.Println(line)
+			}
+		}
+
+		fmt.Printf("\n🎯 Critical Path (%s):\n", unit)
+		if len(path) == 0 {
+			fmt.Println("  All tasks are done.")
+			return
+		}
+		fmt.Printf("  %s\n", strings.Join(path, " → "))
+		fmt.Printf("  Remaining length: %s %s\n", formatWeight(total), unit)
+	},
+}
+
+// dependencyWaves groups task IDs into waves with Kahn's algorithm. A task lands
+// in the first wave after all of its dependencies. Dependencies on tasks that do
+// not exist in the project are ignored. Dependencies name tasks by bare ID, so
+// an ID used in more than one phase is refused rather than guessed at.
+func dependencyWaves(tasks []Task) ([][]string, error) {
+	if err := checkUniqueTaskIDs(tasks); err != nil {
+		return nil, err
+	}
+	taskMap := buildTaskMap(tasks)
+
+	remaining := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, task := range tasks {
+		count := 0
+		for _, depID := range task.DependencyIDs {
+			if _, exists := taskMap[depID]; exists {
+				count++
+				dependents[depID] = append(dependents[depID], task.ID)
+			}
+		}
+		remaining[task.ID] = count
+	}
+
+	var current []string
+	for _, task := range tasks {
+		if remaining[task.ID] == 0 {
+			current = append(current, task.ID)
+		}
+	}
+
+	var waves [][]string
+	placed := 0
+	for len(current) > 0 {
+		sort.Strings(current)
+		waves = append(waves, current)
+		placed += len(current)
+
+		var next []string
+		for _, id := range current {
+			for _, dependent := range dependents[id] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		current = next
+	}
+
+	if placed < len(taskMap) {
+		var cyclic []string
+		for id, count := range remaining {
+			if count > 0 {
+				cyclic = append(cyclic, id)
+			}
+		}
+		sort.Strings(cyclic)
+		return waves, fmt.Errorf("dependency cycle between tasks: %s", strings.Join(cyclic, ", "))
+	}
+
+	return waves, nil
+}
+
+// checkUniqueTaskIDs fails when the same task ID appears more than once,
+// naming the phases that hold each duplicate
+func checkUniqueTaskIDs(tasks []Task) error {
+	phases := make(map[string][]string)
+	for _, task := range tasks {
+		phases[task.ID] = append(phases[task.ID], task.PhaseID)
+	}
+
+	var duplicates []string
+	for id, phaseIDs := range phases {
+		if len(phaseIDs) > 1 {
+			sort.Strings(phaseIDs)
+			duplicates = append(duplicates, fmt.Sprintf("%s (%s)", id, strings.Join(phaseIDs, ", ")))
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	sort.Strings(duplicates)
+	return fmt.Errorf("task IDs used more than once: %s", strings.Join(duplicates, "; "))
+}
+
+// criticalPath returns the heaviest chain of remaining work and its total weight
+func criticalPath(tasks []Task, waves [][]string, unit string) ([]string, float64) {
+	taskMap := buildTaskMap(tasks)
+	distance := make(map[string]float64)
+	previous := make(map[string]string)
+
+	var end string
+	var longest float64
+	for _, wave := range waves {
+		for _, id := range wave {
+			task := taskMap[id]
+			best := 0.0
+			for _, depID := range task.DependencyIDs {
+				if _, exists := taskMap[depID]; !exists {
+					continue
+				}
+				if distance[depID] > best {
+					best = distance[depID]
+					previous[id] = depID
+				}
+			}
+			distance[id] = best + taskWeight(task, unit)
+			if distance[id] > longest {
+				longest = distance[id]
+				end = id
+			}
+		}
+	}
+
+	var path []string
+	for id := end; id != ""; id = previous[id] {
+		if taskMap[id].Status != "done" {
+			path = append([]string{id}, path...)
+		}
+	}
+
+	return path, longest
+}
+
+// resolveWeightUnit picks the weight used for the critical path
+func resolveWeightUnit(weight string, tasks []Task) (string, error) {
+	switch weight {
+	case "hours", "points", "tasks":
+		return weight, nil
+	case "", "auto":
+		for _, task := range tasks {
+			if task.TimeTracking.EstimatedHours > 0 {
+				return "hours", nil
+			}
+		}
+		for _, task := range tasks {
+			if task.StoryPoints > 0 {
+				return "points", nil
+			}
+		}
+		return "tasks", nil
+	default:
+		return "", fmt.Errorf("invalid weight '%s'. Must be one of: auto, hours, points, tasks", weight)
+	}
+}
+
+func taskWeight(task Task, unit string) float64 {
+	if task.Status == "done" {
+		return 0
+	}
+	switch unit {
+	case "hours":
+		if task.TimeTracking.EstimatedHours > 0 {
+			return float64(task.TimeTracking.EstimatedHours)
+		}
+	case "points":
+		if task.StoryPoints > 0 {
+			return float64(task.StoryPoints)
+		}
+	}
+	return 1
+}
+
+func formatWeight(value float64) string {
+	if value == float64(int(value)) {
+		return fmt.Sprintf("%d", int(value))
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+// taskStateIcon matches the icons used by the status commands
+func taskStateIcon(task Task, tasks []Task) string {
+	switch {
+	case task.Status == "done":
+		return "✅"
+	case task.Status == "in_progress":
+		return "🔄"
+	case isTaskBlocked(task, tasks):
+		return "🚫"
+	default:
+		return "📋"
+	}
+}
+
+func init() {
+	statusOrderCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	statusOrderCmd.Flags().String("weight", "auto", "Critical path weight (auto, hours, points, tasks)")
+
+	statusCmd.AddCommand(statusOrderCmd)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// orderTask builds a task with an hour estimate and dependencies
+func orderTask(id, status string, hours int, deps ...string) Task {
+	return Task{ID: id, Status: status, DependencyIDs: deps, TimeTracking: TimeTracking{EstimatedHours: hours}}
+}
+
+func TestDependencyWaves(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []Task
+		want  [][]string
+	}{
+		{
+			name:  "independent tasks share one wave",
+			tasks: []Task{orderTask("T1.2", "todo", 0), orderTask("T1.1", "todo", 0)},
+			want:  [][]string{{"T1.1", "T1.2"}},
+		},
+		{
+			name: "diamond",
+			tasks: []Task{
+				orderTask("T1.1", "todo", 0),
+				orderTask("T1.2", "todo", 0, "T1.1"),
+				orderTask("T1.3", "todo", 0, "T1.1"),
+				orderTask("T1.4", "todo", 0, "T1.2", "T1.3"),
+			},
+			want: [][]string{{"T1.1"}, {"T1.2", "T1.3"}, {"T1.4"}},
+		},
+		{
+			name: "a task waits for its slowest dependency",
+			tasks: []Task{
+				orderTask("T1.1", "todo", 0),
+				orderTask("T1.2", "todo", 0, "T1.1"),
+				orderTask("T1.3", "todo", 0, "T1.1", "T1.2"),
+			},
+			want: [][]string{{"T1.1"}, {"T1.2"}, {"T1.3"}},
+		},
+		{
+			name: "dependencies outside the project are ignored",
+			tasks: []Task{
+				orderTask("T1.1", "todo", 0, "T9.9"),
+				orderTask("T1.2", "todo", 0, "T1.1"),
+			},
+			want: [][]string{{"T1.1"}, {"T1.2"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := dependencyWaves(test.tasks)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("waves = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDependencyWavesCycle(t *testing.T) {
+	tasks := []Task{
+		orderTask("T1.1", "todo", 0),
+		orderTask("T1.2", "todo", 0, "T1.3"),
+		orderTask("T1.3", "todo", 0, "T1.2"),
+	}
+	waves, err := dependencyWaves(tasks)
+	if err == nil {
+		t.Fatal("expected a cycle error")
+	}
+	if !strings.Contains(err.Error(), "T1.2, T1.3") {
+		t.Errorf("error %q does not name the cyclic tasks", err)
+	}
+	if !reflect.DeepEqual(waves, [][]string{{"T1.1"}}) {
+		t.Errorf("waves before the cycle = %v, want [[T1.1]]", waves)
+	}
+}
+
+func TestDependencyWavesRejectsDuplicateIDs(t *testing.T) {
+	first := orderTask("T1.1", "todo", 0)
+	first.PhaseID = "P1"
+	second := orderTask("T1.1", "todo", 0)
+	second.PhaseID = "P2"
+	tasks := []Task{first, second, orderTask("T1.2", "todo", 0, "T1.1")}
+
+	_, err := dependencyWaves(tasks)
+	if err == nil || !strings.Contains(err.Error(), "T1.1 (P1, P2)") {
+		t.Errorf("error %v, want one naming T1.1 and both phases", err)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		tasks     []Task
+		unit      string
+		wantPath  []string
+		wantTotal float64
+	}{
+		{
+			name: "heaviest branch by hours",
+			tasks: []Task{
+				orderTask("T1.1", "todo", 2),
+				orderTask("T1.2", "todo", 8, "T1.1"),
+				orderTask("T1.3", "todo", 3, "T1.1"),
+				orderTask("T1.4", "todo", 1, "T1.2", "T1.3"),
+			},
+			unit:      "hours",
+			wantPath:  []string{"T1.1", "T1.2", "T1.4"},
+			wantTotal: 11,
+		},
+		{
+			name: "longest chain when counting tasks",
+			tasks: []Task{
+				orderTask("T1.1", "todo", 20),
+				orderTask("T1.2", "todo", 1),
+				orderTask("T1.3", "todo", 1, "T1.2"),
+			},
+			unit:      "tasks",
+			wantPath:  []string{"T1.2", "T1.3"},
+			wantTotal: 2,
+		},
+		{
+			name: "done tasks weigh nothing and are left out",
+			tasks: []Task{
+				orderTask("T1.1", "done", 5),
+				orderTask("T1.2", "todo", 3, "T1.1"),
+			},
+			unit:      "hours",
+			wantPath:  []string{"T1.2"},
+			wantTotal: 3,
+		},
+		{
+			name: "tasks without an estimate count as one",
+			tasks: []Task{
+				orderTask("T1.1", "todo", 0),
+				orderTask("T1.2", "todo", 4, "T1.1"),
+			},
+			unit:      "hours",
+			wantPath:  []string{"T1.1", "T1.2"},
+			wantTotal: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			waves, err := dependencyWaves(test.tasks)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			path, total := criticalPath(test.tasks, waves, test.unit)
+			if !reflect.DeepEqual(path, test.wantPath) || total != test.wantTotal {
+				t.Errorf("critical path = %v (%v), want %v (%v)", path, total, test.wantPath, test.wantTotal)
+			}
+		})
+	}
+}
+
+func TestResolveWeightUnit(t *testing.T) {
+	withHours := []Task{orderTask("T1.1", "todo", 3)}
+	withPoints := []Task{{ID: "T1.1", StoryPoints: 5}}
+	plain := []Task{{ID: "T1.1"}}
+
+	tests := []struct {
+		weight string
+		tasks  []Task
+		want   string
+	}{
+		{"auto", withHours, "hours"},
+		{"", withPoints, "points"},
+		{"auto", plain, "tasks"},
+		{"points", withHours, "points"},
+	}
+	for _, test := range tests {
+		got, err := resolveWeightUnit(test.weight, test.tasks)
+		if err != nil || got != test.want {
+			t.Errorf("resolveWeightUnit(%q) = %q, %v, want %q", test.weight, got, err, test.want)
+		}
+	}
+
+	if _, err := resolveWeightUnit("days", plain); err == nil {
+		t.Error("expected an error for an unknown weight")
+	}
+}
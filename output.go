@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat is set by the global --output flag
+var outputFormat = "text"
+
+// TaskRef is the short form of a task used inside status summaries
+type TaskRef struct {
+	ProjectID string `yaml:"project_id" json:"project_id"`
+	PhaseID   string `yaml:"phase_id,omitempty" json:"phase_id,omitempty"`
+	ID        string `yaml:"id" json:"id"`
+	Title     string `yaml:"title" json:"title"`
+	Status    string `yaml:"status" json:"status"`
+	Priority  string `yaml:"priority" json:"priority"`
+}
+
+// StatusCounts mirrors the counters of 'dppm status project'
+type StatusCounts struct {
+	Total      int `yaml:"total" json:"total"`
+	Done       int `yaml:"done" json:"done"`
+	InProgress int `yaml:"in_progress" json:"in_progress"`
+	Ready      int `yaml:"ready" json:"ready"`
+	Blocked    int `yaml:"blocked" json:"blocked"`
+}
+
+// BlockedTask is a todo task together with the unfinished tasks it waits for
+type BlockedTask struct {
+	TaskRef   `yaml:",inline"`
+	BlockedBy []TaskRef `yaml:"blocked_by" json:"blocked_by"`
+}
+
+// ProjectStatus is the machine-readable form of 'dppm status project'
+type ProjectStatus struct {
+	ProjectID string        `yaml:"project_id" json:"project_id"`
+	Counts    StatusCounts  `yaml:"counts" json:"counts"`
+	Ready     []TaskRef     `yaml:"ready" json:"ready"`
+	Blocked   []BlockedTask `yaml:"blocked" json:"blocked"`
+}
+
+// DependencyReport describes the dependencies of one task
+type DependencyReport struct {
+	TaskRef             `yaml:",inline"`
+	DependsOn           []TaskRef `yaml:"depends_on" json:"depends_on"`
+	MissingDependencies []string  `yaml:"missing_dependencies,omitempty" json:"missing_dependencies,omitempty"`
+	Dependents          []TaskRef `yaml:"dependents" json:"dependents"`
+	Blocked             bool      `yaml:"blocked" json:"blocked"`
+	BlockedBy           []TaskRef `yaml:"blocked_by" json:"blocked_by"`
+}
+
+// OrderReport is the machine-readable form of 'dppm status order'
+type OrderReport struct {
+	ProjectID    string             `yaml:"project_id" json:"project_id"`
+	Waves        [][]string         `yaml:"waves" json:"waves"`
+	CriticalPath CriticalPathReport `yaml:"critical_path" json:"critical_path"`
+}
+
+type CriticalPathReport struct {
+	Unit   string   `yaml:"unit" json:"unit"`
+	Tasks  []string `yaml:"tasks" json:"tasks"`
+	Length float64  `yaml:"length" json:"length"`
+}
+
+// printStructured prints v as JSON or YAML when --output asks for it and
+// reports whether it did. Text output is left to the caller.
+func printStructured(v interface{}) bool {
+	switch outputFormat {
+	case "", "text":
+		return false
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding YAML: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(data))
+	default:
+		checkOutputFormat()
+		return false
+	}
+	return true
+}
+
+// checkOutputFormat rejects unknown --output values before any command runs
+func checkOutputFormat() {
+	switch outputFormat {
+	case "", "text", "json", "yaml":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Invalid output format '%s'. Must be one of: text, json, yaml\n", outputFormat)
+		os.Exit(1)
+	}
+}
+
+// structuredOutput reports whether --output selects JSON or YAML
+func structuredOutput() bool {
+	return outputFormat == "json" || outputFormat == "yaml"
+}
+
+func taskRef(task Task) TaskRef {
+	return TaskRef{
+		ProjectID: task.ProjectID,
+		PhaseID:   task.PhaseID,
+		ID:        task.ID,
+		Title:     task.Title,
+		Status:    task.Status,
+		Priority:  task.Priority,
+	}
+}
+
+// blockerRefs lists the unfinished dependencies of a task
+func blockerRefs(task Task, allTasks []Task) []TaskRef {
+	refs := []TaskRef{}
+	taskMap := buildTaskMap(allTasks)
+	for _, depID := range task.DependencyIDs {
+		if depTask, exists := taskMap[depID]; exists && depTask.Status != "done" {
+			refs = append(refs, taskRef(depTask))
+		}
+	}
+	return refs
+}
+
+// buildProjectStatus computes the counts, ready list and blocked list of a project
+func buildProjectStatus(projectID string, tasks []Task) ProjectStatus {
+	status := ProjectStatus{
+		ProjectID: projectID,
+		Ready:     []TaskRef{},
+		Blocked:   []BlockedTask{},
+	}
+	status.Counts.Total = len(tasks)
+
+	for _, task := range tasks {
+		switch task.Status {
+		case "todo":
+			if isTaskBlocked(task, tasks) {
+				status.Counts.Blocked++
+				status.Blocked = append(status.Blocked, BlockedTask{TaskRef: taskRef(task), BlockedBy: blockerRefs(task, tasks)})
+			} else {
+				status.Counts.Ready++
+				status.Ready = append(status.Ready, taskRef(task))
+			}
+		case "in_progress":
+			status.Counts.InProgress++
+		case "done":
+			status.Counts.Done++
+		}
+	}
+
+	return status
+}
+
+// buildDependencyReport collects what a task waits for and what waits for it
+func buildDependencyReport(task Task, tasks []Task) DependencyReport {
+	report := DependencyReport{
+		TaskRef:    taskRef(task),
+		DependsOn:  []TaskRef{},
+		Dependents: []TaskRef{},
+		BlockedBy:  []TaskRef{},
+	}
+
+	taskMap := buildTaskMap(tasks)
+	for _, depID := range task.DependencyIDs {
+		if depTask, exists := taskMap[depID]; exists {
+			report.DependsOn = append(report.DependsOn, taskRef(depTask))
+		} else {
+			report.MissingDependencies = append(report.MissingDependencies, depID)
+		}
+	}
+	for _, t := range tasks {
+		if containsString(t.DependencyIDs, task.ID) {
+			report.Dependents = append(report.Dependents, taskRef(t))
+		}
+	}
+	if task.Status != "done" && isTaskBlocked(task, tasks) {
+		report.Blocked = true
+		report.BlockedBy = blockerRefs(task, tasks)
+	}
+
+	return report
+}
+
+func init() {
+	cobra.OnInitialize(checkOutputFormat)
+}
@@ -3,27 +3,27 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 type Project struct {
-	ID           string                 `yaml:"id"`
-	Name         string                 `yaml:"name"`
-	Description  string                 `yaml:"description"`
-	Status       string                 `yaml:"status"`
-	Owner        string                 `yaml:"owner"`
-	Created      string                 `yaml:"created"`
-	Updated      string                 `yaml:"updated"`
-	Repository   string                 `yaml:"repository,omitempty"`
-	Tags         []string               `yaml:"tags,omitempty"`
-	Metadata     map[string]interface{} `yaml:"metadata,omitempty"`
-	Notes        string                 `yaml:"notes,omitempty"`
-	CurrentPhase string                 `yaml:"current_phase,omitempty"`
-	Phases       []string               `yaml:"phases,omitempty"`
+	ID           string                 `yaml:"id" json:"id"`
+	Name         string                 `yaml:"name" json:"name"`
+	Description  string                 `yaml:"description" json:"description"`
+	Status       string                 `yaml:"status" json:"status"`
+	Owner        string                 `yaml:"owner" json:"owner"`
+	Created      string                 `yaml:"created" json:"created"`
+	Updated      string                 `yaml:"updated" json:"updated"`
+	Revision     int                    `yaml:"revision,omitempty" json:"revision,omitempty"`
+	Repository   string                 `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Tags         []string               `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Metadata     map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Notes        string                 `yaml:"notes,omitempty" json:"notes,omitempty"`
+	CurrentPhase string                 `yaml:"current_phase,omitempty" json:"current_phase,omitempty"`
+	Phases       []string               `yaml:"phases,omitempty" json:"phases,omitempty"`
+	Workflow     *Workflow              `yaml:"workflow,omitempty" json:"workflow,omitempty"`
+	Collab       *CollabConfig          `yaml:"collab,omitempty" json:"collab,omitempty"`
 }
 
 var projectCmd = &cobra.Command{
@@ -43,6 +43,7 @@ Project Structure:
 Available Commands:
   create    Create a new project with specified parameters
   update    Update project metadata and properties
+  workflow  Show the task status workflow
 
 Examples:
   dppm project create web-app --name "Web Application" --owner "dev-team"
@@ -101,6 +102,13 @@ AI Usage Tips:
 			os.Exit(1)
 		}
 
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
 		// Check if project already exists
 		exists, err := CheckProjectExists(projectID)
 		if err != nil {
@@ -145,32 +153,13 @@ AI Usage Tips:
 			Description: description,
 			Status:      "active",
 			Owner:       owner,
-			Created:     time.Now().Format("2006-01-02"),
-			Updated:     time.Now().Format("2006-01-02"),
+			Created:     nowTimestamp(),
+			Updated:     nowTimestamp(),
 			Tags:        []string{},
 			Phases:      []string{},
 		}
 
-		projectDir := filepath.Join(projectsPath, "projects", projectID)
-		if err := os.MkdirAll(projectDir, 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating project directory: %v\n", err)
-			return
-		}
-
-		phasesDir := filepath.Join(projectDir, "phases")
-		if err := os.MkdirAll(phasesDir, 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating phases directory: %v\n", err)
-			return
-		}
-
-		projectFile := filepath.Join(projectDir, "project.yaml")
-		data, err := yaml.Marshal(project)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling project: %v\n", err)
-			return
-		}
-
-		if err := os.WriteFile(projectFile, data, 0644); err != nil {
+		if err := store.SaveProject(&project); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing project file: %v\n", err)
 			return
 		}
@@ -200,7 +189,8 @@ Available Status Values:
 Examples:
   dppm project update web-app --description "Updated project description"
   dppm project update ai-tool --owner "new-team" --status completed
-  dppm project update mobile --name "Mobile App v2.0"`,
+  dppm project update mobile --name "Mobile App v2.0"
+  dppm project update web-app --status paused --if-revision 4`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectID := args[0]
@@ -211,11 +201,17 @@ Examples:
 			os.Exit(1)
 		}
 
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
 		// Check if project exists
-		projectPath := filepath.Join(projectsPath, "projects", projectID, "project.yaml")
-		data, err := os.ReadFile(projectPath)
+		project, err := store.LoadProject(projectID)
 		if err != nil {
-			if os.IsNotExist(err) {
+			if isNotFound(err) {
 				fmt.Fprintf(os.Stderr, "Error: Project '%s' not found\n", projectID)
 			} else {
 				fmt.Fprintf(os.Stderr, "Error reading project: %v\n", err)
@@ -223,9 +219,8 @@ Examples:
 			os.Exit(1)
 		}
 
-		var project Project
-		if err := yaml.Unmarshal(data, &project); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing project file: %v\n", err)
+		if err := checkIfRevision(cmd, "project", projectID, project.Revision); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
@@ -274,16 +269,10 @@ Examples:
 		}
 
 		// Update timestamp
-		project.Updated = time.Now().Format("2006-01-02")
+		project.Updated = nowTimestamp()
 
 		// Write updated project back
-		updatedData, err := yaml.Marshal(project)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling updated project: %v\n", err)
-			os.Exit(1)
-		}
-
-		if err := os.WriteFile(projectPath, updatedData, 0644); err != nil {
+		if err := store.SaveProject(project); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing updated project: %v\n", err)
 			os.Exit(1)
 		}
@@ -302,6 +291,7 @@ func init() {
 	updateProjectCmd.Flags().StringP("description", "d", "", "New project description")
 	updateProjectCmd.Flags().StringP("owner", "o", "", "New project owner")
 	updateProjectCmd.Flags().StringP("status", "s", "", "New project status (active, completed, paused, archived, cancelled)")
+	updateProjectCmd.Flags().Int("if-revision", 0, "Only update if the project is still at this revision")
 
 	projectCmd.AddCommand(createProjectCmd)
 	projectCmd.AddCommand(updateProjectCmd)
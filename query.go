@@ -0,0 +1,560 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query [expression]",
+	Short: "Find tasks across projects with a filter expression",
+	Long: `Query Tasks
+
+Finds tasks in all projects (or one with --project) that match a filter
+expression. The same syntax is accepted by 'dppm list tasks --filter'.
+
+Conditions:
+  field=value     Equal (case-insensitive); value1,value2 matches any of them
+  field!=value    Not equal
+  field>value     Greater than; also >=, < and <=
+  field:value     Contains: substring for text, membership for labels
+
+Conditions are combined with AND (also implied by a space), OR and NOT,
+and can be grouped with parentheses. Quote values containing spaces.
+
+Fields:
+  id, title, description, project, phase, status, assignee, reporter
+  priority          low < medium < high < critical
+  story_points      number (alias: points)
+  estimated_hours   number
+  actual_hours      number
+  due_date          date (alias: due); YYYY-MM-DD, today, today+7, today-3
+  created, updated  date
+  label             the task's labels (alias: labels)
+  depends_on        the task's dependency IDs
+
+A date condition never matches tasks without that date, except 'due_date='
+which finds tasks without a due date.
+
+Examples:
+  dppm query 'status=in_progress AND priority>=high AND label:api AND assignee=gemini'
+  dppm query 'due_date<today AND status!=done'
+  dppm query 'status=todo,blocked story_points>=5' --project web-app
+  dppm query 'title:"login form" OR (label:auth AND NOT status=done)' --output json`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		warnAboutConflicts(projectID)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+
+		filter, err := parseTaskFilter(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid query: %v\n", err)
+			os.Exit(1)
+		}
+
+		projectIDs := []string{projectID}
+		if projectID == "" {
+			if projectIDs, err = allProjectIDs(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		tasks, err := queryTasks(projectIDs, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(tasks) {
+			return
+		}
+		printTaskTable(tasks)
+	},
+}
+
+// taskFilter reports whether a task matches a parsed filter expression
+type taskFilter func(task Task) bool
+
+// queryTasks loads the tasks of the given projects that match filter
+func queryTasks(projectIDs []string, filter taskFilter) ([]Task, error) {
+	matches := []Task{}
+	for _, projectID := range projectIDs {
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tasks of project '%s': %v", projectID, err)
+		}
+		for _, task := range tasks {
+			if filter(task) {
+				matches = append(matches, task)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func printTaskTable(tasks []Task) {
+	if len(tasks) == 0 {
+		fmt.Println("No matching tasks.")
+		return
+	}
+
+	fmt.Printf("%-12s %-14s %-12s %-8s %-12s %-10s %s\n", "ID", "PROJECT", "STATUS", "PRIORITY", "ASSIGNEE", "DUE", "TITLE")
+	for _, task := range tasks {
+		fmt.Printf("%-12s %-14s %-12s %-8s %-12s %-10s %s\n", task.ID, truncate(task.ProjectID, 14), task.Status, task.Priority,
+			truncate(task.Assignee, 12), task.DueDate, truncate(task.Title, 40))
+	}
+	fmt.Printf("\n%d task(s)\n", len(tasks))
+}
+
+// Field kinds decide which operators a field supports and how values compare
+const (
+	fieldText = iota
+	fieldPriority
+	fieldNumber
+	fieldDate
+	fieldList
+)
+
+type queryField struct {
+	kind int
+	text func(task Task) string
+	num  func(task Task) float64
+	list func(task Task) []string
+}
+
+var priorityOrder = []string{"low", "medium", "high", "critical"}
+
+var queryFields = map[string]queryField{
+	"id":              {kind: fieldText, text: func(t Task) string { return t.ID }},
+	"title":           {kind: fieldText, text: func(t Task) string { return t.Title }},
+	"description":     {kind: fieldText, text: func(t Task) string { return t.Description }},
+	"project":         {kind: fieldText, text: func(t Task) string { return t.ProjectID }},
+	"phase":           {kind: fieldText, text: func(t Task) string { return t.PhaseID }},
+	"status":          {kind: fieldText, text: func(t Task) string { return t.Status }},
+	"assignee":        {kind: fieldText, text: func(t Task) string { return t.Assignee }},
+	"reporter":        {kind: fieldText, text: func(t Task) string { return t.Reporter }},
+	"priority":        {kind: fieldPriority, text: func(t Task) string { return t.Priority }},
+	"story_points":    {kind: fieldNumber, num: func(t Task) float64 { return float64(t.StoryPoints) }},
+	"estimated_hours": {kind: fieldNumber, num: func(t Task) float64 { return float64(t.TimeTracking.EstimatedHours) }},
+	"actual_hours":    {kind: fieldNumber, num: func(t Task) float64 { return t.TimeTracking.ActualHours }},
+	"due_date":        {kind: fieldDate, text: func(t Task) string { return t.DueDate }},
+	"created":         {kind: fieldDate, text: func(t Task) string { return t.Created }},
+	"updated":         {kind: fieldDate, text: func(t Task) string { return t.Updated }},
+	"label":           {kind: fieldList, list: func(t Task) []string { return t.Labels }},
+	"depends_on":      {kind: fieldList, list: func(t Task) []string { return t.DependencyIDs }},
+}
+
+var queryFieldAliases = map[string]string{
+	"points": "story_points",
+	"due":    "due_date",
+	"labels": "label",
+}
+
+// Token kinds of the query lexer
+const (
+	tokEOF = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type queryToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+// tokenizeQuery splits a query into words, quoted strings, operators and parentheses
+func tokenizeQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{tokRParen, ")", i})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, queryToken{tokString, string(runes[i+1 : end]), i})
+			i = end + 1
+		case strings.ContainsRune("=!<>:", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != ':' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("expected '!=' at position %d", i+1)
+			}
+			tokens = append(tokens, queryToken{tokOp, op, i})
+			i += len(op)
+			// Accept == as a spelling of =
+			if op == "=" && i < len(runes) && runes[i] == '=' {
+				i++
+			}
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()\"'=!<>:", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, queryToken{tokWord, string(runes[start:i]), start})
+		}
+	}
+	return append(tokens, queryToken{tokEOF, "", len(runes)}), nil
+}
+
+// queryParser is a recursive descent parser for:
+//
+//	expr       = and { "OR" and }
+//	and        = unary { ["AND"] unary }
+//	unary      = "NOT" unary | "(" expr ")" | condition
+//	condition  = field op [value]
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+// parseTaskFilter parses a filter expression; an empty expression matches every task
+func parseTaskFilter(expr string) (taskFilter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return func(Task) bool { return true }, nil
+	}
+
+	tokens, err := tokenizeQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", tok.text, tok.pos+1)
+	}
+	return filter, nil
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *queryParser) parseOr() (taskFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t Task) bool { return l(t) || right(t) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (taskFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if p.isKeyword("AND") {
+			p.next()
+		} else if tok := p.peek(); tok.kind == tokEOF || tok.kind == tokRParen || p.isKeyword("OR") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t Task) bool { return l(t) && right(t) }
+	}
+}
+
+func (p *queryParser) parseUnary() (taskFilter, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t Task) bool { return !inner(t) }, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		open := p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("missing ')' for '(' at position %d", open.pos+1)
+		}
+		p.next()
+		return inner, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *queryParser) parseCondition() (taskFilter, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokWord {
+		if fieldTok.kind == tokEOF {
+			return nil, fmt.Errorf("expected a condition at end of query")
+		}
+		return nil, fmt.Errorf("expected a field name at position %d, got '%s'", fieldTok.pos+1, fieldTok.text)
+	}
+
+	name := strings.ToLower(fieldTok.text)
+	if alias, ok := queryFieldAliases[name]; ok {
+		name = alias
+	}
+	field, ok := queryFields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field '%s' at position %d", fieldTok.text, fieldTok.pos+1)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator after '%s' at position %d", fieldTok.text, opTok.pos+1)
+	}
+
+	// The value may be empty, e.g. 'due_date=' for tasks without a due date,
+	// so a word followed by an operator starts the next condition instead
+	value := ""
+	if tok := p.peek(); tok.kind == tokString {
+		value = p.next().text
+	} else if tok.kind == tokWord && !p.isKeyword("AND") && !p.isKeyword("OR") && p.tokens[p.pos+1].kind != tokOp {
+		value = p.next().text
+	}
+
+	filter, err := buildCondition(field, opTok.text, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s%s: %v", fieldTok.text, opTok.text, value, err)
+	}
+	return filter, nil
+}
+
+// buildCondition turns one field/operator/value triple into a filter
+func buildCondition(field queryField, op, value string) (taskFilter, error) {
+	switch field.kind {
+	case fieldText:
+		values := splitQueryValues(value)
+		switch op {
+		case "=":
+			return func(t Task) bool { return matchesAny(field.text(t), values, strings.EqualFold) }, nil
+		case "!=":
+			return func(t Task) bool { return !matchesAny(field.text(t), values, strings.EqualFold) }, nil
+		case ":":
+			return func(t Task) bool { return matchesAny(field.text(t), values, containsFold) }, nil
+		}
+		return nil, fmt.Errorf("text fields support =, != and : only")
+
+	case fieldPriority:
+		var ranks []int
+		for _, v := range splitQueryValues(value) {
+			rank := indexOf(priorityOrder, strings.ToLower(v))
+			if rank < 0 {
+				return nil, fmt.Errorf("unknown priority '%s' (use %s)", v, strings.Join(priorityOrder, ", "))
+			}
+			ranks = append(ranks, rank)
+		}
+		if len(ranks) == 0 {
+			return nil, fmt.Errorf("missing priority")
+		}
+		switch op {
+		case "=", ":", "!=":
+			return func(t Task) bool {
+				rank := indexOf(priorityOrder, strings.ToLower(t.Priority))
+				for _, want := range ranks {
+					if rank == want {
+						return op != "!="
+					}
+				}
+				return op == "!="
+			}, nil
+		}
+		if len(ranks) > 1 {
+			return nil, fmt.Errorf("lists of values only work with = and !=")
+		}
+		return func(t Task) bool {
+			rank := indexOf(priorityOrder, strings.ToLower(t.Priority))
+			return rank >= 0 && compareOp(op, float64(rank), float64(ranks[0]))
+		}, nil
+
+	case fieldNumber:
+		want, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", value)
+		}
+		if op == ":" {
+			op = "="
+		}
+		return func(t Task) bool { return compareOp(op, field.num(t), want) }, nil
+
+	case fieldDate:
+		if value == "" {
+			switch op {
+			case "=":
+				return func(t Task) bool { return field.text(t) == "" }, nil
+			case "!=":
+				return func(t Task) bool { return field.text(t) != "" }, nil
+			}
+			return nil, fmt.Errorf("missing date")
+		}
+		want, err := parseQueryDate(value)
+		if err != nil {
+			return nil, err
+		}
+		if op == ":" {
+			op = "="
+		}
+		return func(t Task) bool {
+			have := dateOnly(field.text(t))
+			if have == "" {
+				return false
+			}
+			return compareOp(op, float64(strings.Compare(have, want)), 0)
+		}, nil
+
+	case fieldList:
+		values := splitQueryValues(value)
+		member := func(t Task) bool {
+			for _, item := range field.list(t) {
+				if matchesAny(item, values, strings.EqualFold) {
+					return true
+				}
+			}
+			return false
+		}
+		switch op {
+		case "=", ":":
+			return member, nil
+		case "!=":
+			return func(t Task) bool { return !member(t) }, nil
+		}
+		return nil, fmt.Errorf("list fields support =, != and : only")
+	}
+	return nil, fmt.Errorf("unsupported field")
+}
+
+// dateOnly cuts a YYYY-MM-DD date or RFC3339 timestamp down to its date
+func dateOnly(value string) string {
+	if len(value) >= len(dateLayout) {
+		return value[:len(dateLayout)]
+	}
+	return value
+}
+
+// parseQueryDate accepts YYYY-MM-DD, today, today+N and today-N (days)
+func parseQueryDate(value string) (string, error) {
+	lower := strings.ToLower(value)
+	if strings.HasPrefix(lower, "today") {
+		days := 0
+		if offset := lower[len("today"):]; offset != "" {
+			n, err := strconv.Atoi(offset)
+			if err != nil {
+				return "", fmt.Errorf("invalid date offset in '%s' (use e.g. today+7)", value)
+			}
+			days = n
+		}
+		return time.Now().AddDate(0, 0, days).Format(dateLayout), nil
+	}
+	if _, err := time.Parse(dateLayout, value); err != nil {
+		return "", fmt.Errorf("invalid date '%s' (expected YYYY-MM-DD or today+N)", value)
+	}
+	return value, nil
+}
+
+func compareOp(op string, have, want float64) bool {
+	switch op {
+	case "=":
+		return have == want
+	case "!=":
+		return have != want
+	case ">":
+		return have > want
+	case ">=":
+		return have >= want
+	case "<":
+		return have < want
+	case "<=":
+		return have <= want
+	}
+	return false
+}
+
+func splitQueryValues(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		values = append(values, strings.TrimSpace(part))
+	}
+	return values
+}
+
+func matchesAny(have string, values []string, match func(have, want string) bool) bool {
+	for _, want := range values {
+		if match(have, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(have, want string) bool {
+	return strings.Contains(strings.ToLower(have), strings.ToLower(want))
+}
+
+func indexOf(list []string, value string) int {
+	for i, item := range list {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func init() {
+	queryCmd.Flags().StringP("project", "p", "", "Only query this project (default: all projects)")
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var queryTestTasks = []Task{
+	{
+		ID: "T1.1", Title: "Login form", ProjectID: "web", PhaseID: "P1", Status: "in_progress",
+		Priority: "high", Assignee: "gemini", StoryPoints: 5, DueDate: "2026-10-01",
+		Labels: []string{"api", "auth"}, Created: "2026-09-01T10:00:00Z",
+	},
+	{
+		ID: "T1.2", Title: "Logout", ProjectID: "web", PhaseID: "P1", Status: "todo",
+		Priority: "critical", Assignee: "claude", StoryPoints: 2,
+		DependencyIDs: []string{"T1.1"}, Created: "2026-09-15",
+	},
+	{
+		ID: "T2.1", Title: "Docs", ProjectID: "mobile", PhaseID: "P2", Status: "done",
+		Priority: "low", StoryPoints: 8, DueDate: "2026-11-20",
+		Labels: []string{"docs"}, TimeTracking: TimeTracking{EstimatedHours: 4, ActualHours: 5.5},
+	},
+}
+
+// matchingIDs runs a query over queryTestTasks
+func matchingIDs(t *testing.T, expr string) []string {
+	t.Helper()
+	filter, err := parseTaskFilter(expr)
+	if err != nil {
+		t.Fatalf("parseTaskFilter(%q): %v", expr, err)
+	}
+	ids := []string{}
+	for _, task := range queryTestTasks {
+		if filter(task) {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids
+}
+
+func TestParseTaskFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"", []string{"T1.1", "T1.2", "T2.1"}},
+		{"status=in_progress AND priority>=high AND label:api AND assignee=gemini", []string{"T1.1"}},
+		{"STATUS=TODO", []string{"T1.2"}},
+		{"status==todo", []string{"T1.2"}},
+		{"status=todo,done", []string{"T1.2", "T2.1"}},
+		{"status!=done", []string{"T1.1", "T1.2"}},
+		{"priority>=high", []string{"T1.1", "T1.2"}},
+		{"priority<medium", []string{"T2.1"}},
+		{"priority=low,critical", []string{"T1.2", "T2.1"}},
+		{"points>=5", []string{"T1.1", "T2.1"}},
+		{"story_points<3", []string{"T1.2"}},
+		{"actual_hours>5", []string{"T2.1"}},
+		{"estimated_hours=4", []string{"T2.1"}},
+		{"title:log", []string{"T1.1", "T1.2"}},
+		{`title:"login form"`, []string{"T1.1"}},
+		{"title:'LOGIN FORM'", []string{"T1.1"}},
+		{"labels:auth", []string{"T1.1"}},
+		{"label!=api", []string{"T1.2", "T2.1"}},
+		{"depends_on=T1.1", []string{"T1.2"}},
+		{"due_date<2026-10-15", []string{"T1.1"}},
+		{"due>=2026-10-01", []string{"T1.1", "T2.1"}},
+		{"due_date=", []string{"T1.2"}},
+		{"due_date!=", []string{"T1.1", "T2.1"}},
+		{"created<2026-09-10", []string{"T1.1"}},
+		{"created:2026-09-15", []string{"T1.2"}},
+		{"project=web phase=P1 assignee=claude", []string{"T1.2"}},
+		{"status=done OR priority=critical", []string{"T1.2", "T2.1"}},
+		{"status=done OR status=todo AND assignee=claude", []string{"T1.2", "T2.1"}},
+		{"(status=done OR status=todo) AND assignee=claude", []string{"T1.2"}},
+		{"NOT status=done", []string{"T1.1", "T1.2"}},
+		{"NOT (label:api OR label:docs)", []string{"T1.2"}},
+		{"label:auth AND NOT status=done", []string{"T1.1"}},
+		{"assignee= status=done", []string{"T2.1"}},
+	}
+
+	for _, test := range tests {
+		if got := matchingIDs(t, test.expr); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q matched %v, want %v", test.expr, got, test.want)
+		}
+	}
+}
+
+func TestParseTaskFilterRelativeDates(t *testing.T) {
+	today := time.Now().Format(dateLayout)
+	tasks := []Task{
+		{ID: "past", DueDate: time.Now().AddDate(0, 0, -3).Format(dateLayout)},
+		{ID: "today", DueDate: today},
+		{ID: "soon", DueDate: time.Now().AddDate(0, 0, 5).Format(dateLayout)},
+		{ID: "later", DueDate: time.Now().AddDate(0, 0, 30).Format(dateLayout)},
+	}
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"due_date<today", []string{"past"}},
+		{"due=today", []string{"today"}},
+		{"due>=today due<=today+7", []string{"today", "soon"}},
+		{"due>today-5 due<today+1", []string{"past", "today"}},
+	}
+	for _, test := range tests {
+		filter, err := parseTaskFilter(test.expr)
+		if err != nil {
+			t.Fatalf("parseTaskFilter(%q): %v", test.expr, err)
+		}
+		got := []string{}
+		for _, task := range tasks {
+			if filter(task) {
+				got = append(got, task.ID)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q matched %v, want %v", test.expr, got, test.want)
+		}
+	}
+}
+
+func TestParseTaskFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		message string
+	}{
+		{"colour=red", "unknown field 'colour'"},
+		{"status", "expected an operator"},
+		{"status=todo AND", "expected a condition at end of query"},
+		{"(status=todo", "missing ')'"},
+		{`title:"open`, "unterminated string"},
+		{"status!todo", "expected '!='"},
+		{"priority>=urgent", "unknown priority 'urgent'"},
+		{"priority>low,high", "lists of values only work with = and !="},
+		{"points>=many", "'many' is not a number"},
+		{"due<tomorrow", "invalid date 'tomorrow'"},
+		{"due<today+x", "invalid date offset"},
+		{"title>abc", "text fields support =, != and : only"},
+		{"label>api", "list fields support =, != and : only"},
+		{"due>", "missing date"},
+		{"= todo", "expected a field name at position 1"},
+	}
+	for _, test := range tests {
+		_, err := parseTaskFilter(test.expr)
+		if err == nil {
+			t.Errorf("%q: expected an error", test.expr)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.message) {
+			t.Errorf("%q: error %q does not contain %q", test.expr, err, test.message)
+		}
+	}
+}
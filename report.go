@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate project reports",
+	Long: `Report Commands
+
+Summaries built from the data stored on tasks.
+
+Available Subcommands:
+  time      Compare estimated hours with logged hours
+
+Examples:
+  dppm report time --project web-app
+  dppm report time --project web-app --since 2026-10-01 --by author`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		warnAboutConflicts(projectID)
+	},
+}
+
+var reportTimeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Compare estimated hours with logged hours",
+	Long: `Time Report
+
+Compares time_tracking.estimated_hours with the hours logged through
+'dppm task log' for every task of a project, then groups the totals.
+
+With --since only time logs on or after that date are counted. Estimates are
+always counted in full.
+
+Grouping (--by):
+  phase     Estimated vs. actual hours per phase (default)
+  author    Logged hours per author
+
+Examples:
+  dppm report time --project web-app
+  dppm report time --project web-app --since 2026-10-01
+  dppm report time --project web-app --by author --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		since, _ := cmd.Flags().GetString("since")
+		by, _ := cmd.Flags().GetString("by")
+
+		if projectID == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project is required")
+			os.Exit(1)
+		}
+		if by != "phase" && by != "author" {
+			fmt.Fprintf(os.Stderr, "Error: Invalid grouping '%s'. Must be one of: phase, author\n", by)
+			os.Exit(1)
+		}
+		var sinceDate time.Time
+		if since != "" {
+			var err error
+			if sinceDate, err = time.Parse(dateLayout, since); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Invalid date '%s' (expected YYYY-MM-DD)\n", since)
+				os.Exit(1)
+			}
+		}
+
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+
+		report := buildTimeReport(projectID, tasks, sinceDate, by)
+		report.Since = since
+		if printStructured(report) {
+			return
+		}
+
+		fmt.Printf("Time Report: %s", projectID)
+		if since != "" {
+			fmt.Printf(" (since %s)", since)
+		}
+		fmt.Println()
+		fmt.Println("=====================")
+
+		if len(report.Tasks) == 0 {
+			fmt.Println("No estimates or logged time.")
+			return
+		}
+
+		fmt.Println("\nTasks:")
+		for _, task := range report.Tasks {
+			fmt.Printf("  %s %-8s %-30s estimated %5sh  actual %5sh%s\n", timeIcon(task.EstimatedHours, task.ActualHours), task.ID, truncate(task.Title, 30),
+				formatHours(task.EstimatedHours), formatHours(task.ActualHours), varianceLabel(task.EstimatedHours, task.VarianceHours))
+		}
+
+		if by == "author" {
+			fmt.Println("\nBy Author:")
+		} else {
+			fmt.Println("\nBy Phase:")
+		}
+		for _, group := range report.Groups {
+			if by == "author" {
+				fmt.Printf("  👤 %-20s %5sh on %d task(s)\n", group.Key, formatHours(group.ActualHours), group.Tasks)
+				continue
+			}
+			fmt.Printf("  %s %-20s estimated %5sh  actual %5sh%s\n", timeIcon(group.EstimatedHours, group.ActualHours), group.Key,
+				formatHours(group.EstimatedHours), formatHours(group.ActualHours), varianceLabel(group.EstimatedHours, group.VarianceHours))
+		}
+
+		fmt.Printf("\nTotal: estimated %sh, actual %sh%s\n", formatHours(report.Total.EstimatedHours), formatHours(report.Total.ActualHours),
+			varianceLabel(report.Total.EstimatedHours, report.Total.VarianceHours))
+	},
+}
+
+// TimeReport is the result of 'dppm report time'
+type TimeReport struct {
+	ProjectID string      `yaml:"project_id" json:"project_id"`
+	Since     string      `yaml:"since,omitempty" json:"since,omitempty"`
+	By        string      `yaml:"by" json:"by"`
+	Tasks     []TaskTime  `yaml:"tasks" json:"tasks"`
+	Groups    []TimeGroup `yaml:"groups" json:"groups"`
+	Total     TimeGroup   `yaml:"total" json:"total"`
+}
+
+// TaskTime compares the estimate of one task with its logged hours
+type TaskTime struct {
+	TaskRef        `yaml:",inline"`
+	EstimatedHours float64 `yaml:"estimated_hours" json:"estimated_hours"`
+	ActualHours    float64 `yaml:"actual_hours" json:"actual_hours"`
+	VarianceHours  float64 `yaml:"variance_hours" json:"variance_hours"`
+}
+
+// TimeGroup sums hours per phase or author. Author groups carry no estimate.
+type TimeGroup struct {
+	Key            string  `yaml:"key" json:"key"`
+	EstimatedHours float64 `yaml:"estimated_hours" json:"estimated_hours"`
+	ActualHours    float64 `yaml:"actual_hours" json:"actual_hours"`
+	VarianceHours  float64 `yaml:"variance_hours" json:"variance_hours"`
+	Tasks          int     `yaml:"tasks" json:"tasks"`
+}
+
+// buildTimeReport sums logged hours on or after since (zero time counts all logs)
+func buildTimeReport(projectID string, tasks []Task, since time.Time, by string) TimeReport {
+	report := TimeReport{ProjectID: projectID, By: by, Tasks: []TaskTime{}, Groups: []TimeGroup{}}
+	groups := make(map[string]*TimeGroup)
+	authorTasks := make(map[string]map[string]bool)
+
+	group := func(key string) *TimeGroup {
+		if groups[key] == nil {
+			groups[key] = &TimeGroup{Key: key}
+		}
+		return groups[key]
+	}
+
+	for _, task := range tasks {
+		entry := TaskTime{TaskRef: taskRef(task), EstimatedHours: float64(task.TimeTracking.EstimatedHours)}
+		for _, log := range task.TimeTracking.TimeLogs {
+			if !since.IsZero() && parseTimestamp(log.Date).Before(since) {
+				continue
+			}
+			entry.ActualHours += float64(log.Hours)
+
+			if by == "author" {
+				author := log.Author
+				if author == "" {
+					author = "(unknown)"
+				}
+				group(author).ActualHours += float64(log.Hours)
+				if authorTasks[author] == nil {
+					authorTasks[author] = make(map[string]bool)
+				}
+				authorTasks[author][task.ID] = true
+			}
+		}
+		if entry.EstimatedHours == 0 && entry.ActualHours == 0 {
+			continue
+		}
+		entry.ActualHours = roundHours(entry.ActualHours)
+		entry.VarianceHours = roundHours(entry.ActualHours - entry.EstimatedHours)
+		report.Tasks = append(report.Tasks, entry)
+
+		if by == "phase" {
+			key := task.PhaseID
+			if key == "" {
+				key = "(no phase)"
+			}
+			g := group(key)
+			g.EstimatedHours += entry.EstimatedHours
+			g.ActualHours += entry.ActualHours
+			g.Tasks++
+		}
+
+		report.Total.EstimatedHours += entry.EstimatedHours
+		report.Total.ActualHours += entry.ActualHours
+		report.Total.Tasks++
+	}
+
+	for author, taskIDs := range authorTasks {
+		groups[author].Tasks = len(taskIDs)
+	}
+
+	for _, g := range groups {
+		g.ActualHours = roundHours(g.ActualHours)
+		if by == "phase" {
+			g.VarianceHours = roundHours(g.ActualHours - g.EstimatedHours)
+		}
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].Key < report.Groups[j].Key })
+
+	report.Total.Key = "total"
+	report.Total.ActualHours = roundHours(report.Total.ActualHours)
+	report.Total.VarianceHours = roundHours(report.Total.ActualHours - report.Total.EstimatedHours)
+	return report
+}
+
+// timeIcon flags work that has run over its estimate
+func timeIcon(estimated, actual float64) string {
+	switch {
+	case estimated == 0:
+		return "❔"
+	case actual > estimated:
+		return "⚠️ "
+	default:
+		return "✅"
+	}
+}
+
+func varianceLabel(estimated, variance float64) string {
+	if estimated == 0 {
+		return "  (no estimate)"
+	}
+	return fmt.Sprintf("  (%+gh)", variance)
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
+func init() {
+	reportTimeCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	reportTimeCmd.Flags().String("since", "", "Only count time logged on or after this date (YYYY-MM-DD)")
+	reportTimeCmd.Flags().String("by", "phase", "Group totals by phase or author")
+
+	reportCmd.AddCommand(reportTimeCmd)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// ErrRevisionConflict is returned when an entity changed after it was read
+var ErrRevisionConflict = errors.New("revision conflict")
+
+// revisionConflictError describes which revision was expected and which one was found
+func revisionConflictError(kind, id string, stored, expected int) error {
+	return fmt.Errorf("%w: %s '%s' is at revision %d, expected %d", ErrRevisionConflict, kind, id, stored, expected)
+}
+
+// checkIfRevision enforces the --if-revision flag of update commands. Scripted
+// agents pass the revision they last read; the update is refused when the
+// stored revision has moved on since then.
+func checkIfRevision(cmd *cobra.Command, kind, id string, current int) error {
+	if !cmd.Flags().Changed("if-revision") {
+		return nil
+	}
+	expected, _ := cmd.Flags().GetInt("if-revision")
+	if current != expected {
+		return revisionConflictError(kind, id, current, expected)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestStoresRefuseStaleSaves(t *testing.T) {
+	stores := map[string]Store{
+		"yaml":   NewYAMLStore(t.TempDir()),
+		"memory": NewMemoryStore(),
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			task := &Task{ID: "T1.1", ProjectID: "demo", PhaseID: "P1", Title: "First"}
+			if err := s.SaveTask(task); err != nil {
+				t.Fatal(err)
+			}
+			if task.Revision != 1 {
+				t.Errorf("revision after first save = %d, want 1", task.Revision)
+			}
+
+			stale, err := s.LoadTask("demo", "T1.1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			task.Title = "Second"
+			if err := s.SaveTask(task); err != nil || task.Revision != 2 {
+				t.Fatalf("second save: revision %d, %v", task.Revision, err)
+			}
+
+			stale.Title = "Lost update"
+			if err := s.SaveTask(stale); !errors.Is(err, ErrRevisionConflict) {
+				t.Errorf("saving a stale copy: %v, want a revision conflict", err)
+			}
+			stored, _ := s.LoadTask("demo", "T1.1")
+			if stored.Title != "Second" || stored.Revision != 2 {
+				t.Errorf("stored task = %q at revision %d", stored.Title, stored.Revision)
+			}
+
+			phase := &Phase{ID: "P1", ProjectID: "demo"}
+			if err := s.SavePhase(phase); err != nil {
+				t.Fatal(err)
+			}
+			phase.Revision = 0
+			if err := s.SavePhase(phase); !errors.Is(err, ErrRevisionConflict) {
+				t.Errorf("saving a stale phase: %v, want a revision conflict", err)
+			}
+		})
+	}
+}
+
+func TestCheckIfRevision(t *testing.T) {
+	tests := []struct {
+		args     []string
+		conflict bool
+	}{
+		{nil, false},
+		{[]string{"--if-revision", "3"}, false},
+		{[]string{"--if-revision", "2"}, true},
+	}
+	for _, test := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("if-revision", 0, "")
+		if err := cmd.ParseFlags(test.args); err != nil {
+			t.Fatal(err)
+		}
+		err := checkIfRevision(cmd, "task", "T1.1", 3)
+		if got := errors.Is(err, ErrRevisionConflict); got != test.conflict {
+			t.Errorf("%v: error %v, want conflict %v", test.args, err, test.conflict)
+		}
+	}
+}
+
+func TestUpdateTaskChecksIfRevision(t *testing.T) {
+	store = NewMemoryStore()
+	task := &Task{ID: "T1.1", ProjectID: "demo", PhaseID: "P1", Title: "First"}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(args ...string) bool {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("if-revision", 0, "")
+		cmd.Flags().String("title", "", "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return updateTask("demo", "T1.1", cmd)
+	}
+
+	if update("--title", "Stale", "--if-revision", "0") {
+		t.Error("update with an old revision was applied")
+	}
+	if !update("--title", "Current", "--if-revision", "1") {
+		t.Error("update with the current revision was refused")
+	}
+	stored, _ := store.LoadTask("demo", "T1.1")
+	if stored.Title != "Current" || stored.Revision != 2 {
+		t.Errorf("stored task = %q at revision %d", stored.Title, stored.Revision)
+	}
+}
@@ -233,22 +233,26 @@ func (s *FirstRunSetup) hasProjectStructure() bool {
 
 // hasPermissions verifies read/write access to Dropbox
 func (s *FirstRunSetup) hasPermissions() bool {
-	// Test write access
-	testFile := filepath.Join(s.DropboxPath, ".dppm-permission-test")
+	// Test write access with a uniquely named file so concurrent
+	// dppm invocations never remove each other's probe
+	testFile, err := os.CreateTemp(s.DropboxPath, ".dppm-permission-test-*")
+	if err != nil {
+		return false
+	}
+	defer os.Remove(testFile.Name())
 
-	// Try to create test file
-	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+	// Try to write to it
+	if _, err := testFile.Write([]byte("test")); err != nil {
+		testFile.Close()
 		return false
 	}
+	testFile.Close()
 
 	// Try to read it back
-	if _, err := os.ReadFile(testFile); err != nil {
+	if _, err := os.ReadFile(testFile.Name()); err != nil {
 		return false
 	}
 
-	// Clean up test file
-	os.Remove(testFile)
-
 	return true
 }
 
@@ -322,7 +326,7 @@ ai_instructions: |
 
 // showFirstRunGuide displays comprehensive setup instructions for AI
 func showFirstRunGuide() error {
-	fmt.Println(`
+	fmt.Print(`
 🤖 DPPM FIRST-RUN SETUP GUIDE FOR AI
 ===================================
 
@@ -330,6 +334,7 @@ DPPM requires proper Dropbox installation and setup before use.
 Follow this guide step-by-step to ensure correct configuration.
 
 ⚠️  CRITICAL: Do NOT proceed until ALL steps are completed!
+
 `)
 
 	setup, err := validateDropboxInstallation()
@@ -3,34 +3,33 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 type Phase struct {
-	ID        string       `yaml:"id"`
-	Name      string       `yaml:"name"`
-	ProjectID string       `yaml:"project_id"`
-	Status    string       `yaml:"status"`
-	StartDate string       `yaml:"start_date,omitempty"`
-	EndDate   string       `yaml:"end_date,omitempty"`
-	Created   string       `yaml:"created"`
-	Updated   string       `yaml:"updated"`
-	Goal      string       `yaml:"goal,omitempty"`
-	Capacity  int          `yaml:"capacity,omitempty"`
-	Tasks     []string     `yaml:"tasks,omitempty"`
-	Metrics   PhaseMetrics `yaml:"metrics,omitempty"`
-	Notes     string       `yaml:"notes,omitempty"`
+	ID        string       `yaml:"id" json:"id"`
+	Name      string       `yaml:"name" json:"name"`
+	ProjectID string       `yaml:"project_id" json:"project_id"`
+	Status    string       `yaml:"status" json:"status"`
+	StartDate string       `yaml:"start_date,omitempty" json:"start_date,omitempty"`
+	EndDate   string       `yaml:"end_date,omitempty" json:"end_date,omitempty"`
+	Created   string       `yaml:"created" json:"created"`
+	Updated   string       `yaml:"updated" json:"updated"`
+	Revision  int          `yaml:"revision,omitempty" json:"revision,omitempty"`
+	Goal      string       `yaml:"goal,omitempty" json:"goal,omitempty"`
+	Capacity  int          `yaml:"capacity,omitempty" json:"capacity,omitempty"`
+	Tasks     []string     `yaml:"tasks,omitempty" json:"tasks,omitempty"`
+	Metrics   PhaseMetrics `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	Notes     string       `yaml:"notes,omitempty" json:"notes,omitempty"`
 }
 
 type PhaseMetrics struct {
-	CompletedTasks       int `yaml:"completed_tasks"`
-	TotalTasks           int `yaml:"total_tasks"`
-	StoryPointsCompleted int `yaml:"story_points_completed"`
-	StoryPointsTotal     int `yaml:"story_points_total"`
+	CompletedTasks       int `yaml:"completed_tasks" json:"completed_tasks"`
+	TotalTasks           int `yaml:"total_tasks" json:"total_tasks"`
+	StoryPointsCompleted int `yaml:"story_points_completed" json:"story_points_completed"`
+	StoryPointsTotal     int `yaml:"story_points_total" json:"story_points_total"`
 }
 
 var phaseCmd = &cobra.Command{
@@ -57,11 +56,13 @@ Available Commands:
   list      List all phases in a project
   show      Display detailed phase information
   update    Update phase metadata
+  close     Complete a phase (open tasks must be done or moved)
 
 Examples:
   dppm phase create phase-3 --project dash-lxd --name "File Integration Phase"
   dppm phase list --project dash-lxd
   dppm phase show phase-3 --project dash-lxd
+  dppm phase close P1 --project dash-lxd --move-open-to P2
 
 For more information about a specific command, use:
   dppm phase [command] --help`,
@@ -111,13 +112,24 @@ Examples:
 		}
 
 		// Check if project exists
-		projectPath := filepath.Join(projectsPath, "projects", projectID)
-		if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		exists, err := store.ProjectExists(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking project existence: %v\n", err)
+			os.Exit(1)
+		}
+		if !exists {
 			fmt.Fprintf(os.Stderr, "Error: Project '%s' does not exist\n", projectID)
 			fmt.Fprintf(os.Stderr, "Create it first with: dppm project create %s\n", projectID)
 			os.Exit(1)
 		}
 
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
 		if name == "" {
 			name = phaseID
 		}
@@ -137,42 +149,409 @@ Examples:
 			Status:    "planning",
 			StartDate: startDate,
 			EndDate:   endDate,
-			Created:   time.Now().Format("2006-01-02"),
-			Updated:   time.Now().Format("2006-01-02"),
+			Created:   nowTimestamp(),
+			Updated:   nowTimestamp(),
 			Goal:      goal,
 			Tasks:     []string{},
 		}
 
-		// Create phase directory structure
-		phaseDir := filepath.Join(projectsPath, "projects", projectID, "phases", phaseID)
-		if err := os.MkdirAll(phaseDir, 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating phase directory: %v\n", err)
+		if err := store.SavePhase(&phase); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing phase file: %v\n", err)
 			return
 		}
 
-		tasksDir := filepath.Join(phaseDir, "tasks")
-		if err := os.MkdirAll(tasksDir, 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating tasks directory: %v\n", err)
+		fmt.Printf("Phase '%s' created successfully in project '%s'\n", phaseID, projectID)
+	},
+}
+
+var listPhasesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all phases in a project",
+	Long: `List Phases
+
+Lists the phases of a project with their status and task metrics. Metrics
+are computed from the phase's tasks every time, so they are never stale.
+
+Examples:
+  dppm phase list --project web-app
+  dppm phase list --project web-app --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+
+		phases, tasks, err := loadPhasesWithMetrics(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(phases) {
+			return
+		}
+
+		fmt.Printf("Phases in %s:\n", projectID)
+		fmt.Println("=================")
+
+		if len(phases) == 0 {
+			fmt.Println("No phases found.")
+			fmt.Printf("Create one with: dppm phase create P1 --project %s\n", projectID)
+			return
+		}
+
+		for _, phase := range phases {
+			fmt.Printf("%s %s - %s (%s)\n", phaseStatusIcon(phase.Status), phase.ID, phase.Name, phase.Status)
+			fmt.Printf("   Tasks: %d/%d done", phase.Metrics.CompletedTasks, phase.Metrics.TotalTasks)
+			if phase.Metrics.StoryPointsTotal > 0 {
+				fmt.Printf("  Story Points: %d/%d", phase.Metrics.StoryPointsCompleted, phase.Metrics.StoryPointsTotal)
+			}
+			fmt.Println()
+		}
+
+		if unphased := countUnphasedTasks(tasks); unphased > 0 {
+			fmt.Printf("\n%d task(s) are not assigned to a phase\n", unphased)
+		}
+	},
+}
+
+var showPhaseCmd = &cobra.Command{
+	Use:   "show [phase-id]",
+	Short: "Display detailed phase information",
+	Long: `Show Phase Details
+
+Shows a phase's metadata, its computed metrics and its tasks.
+
+Examples:
+  dppm phase show P1 --project web-app
+  dppm phase show P1 --project web-app --output yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		phaseID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+
+		phase, err := store.LoadPhase(projectID, phaseID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Phase '%s' not found in project '%s'\n", phaseID, projectID)
+			os.Exit(1)
+		}
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+		phaseTasks := tasksInPhase(tasks, phaseID)
+		phase.Metrics = computePhaseMetrics(phaseTasks)
+
+		if printStructured(phase) {
 			return
 		}
 
-		phaseFile := filepath.Join(phaseDir, "phase.yaml")
-		data, err := yaml.Marshal(phase)
+		fmt.Printf("Phase: %s\n", phase.ID)
+		fmt.Printf("================\n\n")
+		fmt.Printf("Name: %s\n", phase.Name)
+		fmt.Printf("Project: %s\n", phase.ProjectID)
+		fmt.Printf("Status: %s\n", phase.Status)
+		if phase.Goal != "" {
+			fmt.Printf("Goal: %s\n", phase.Goal)
+		}
+		if phase.StartDate != "" || phase.EndDate != "" {
+			fmt.Printf("Dates: %s → %s\n", phase.StartDate, phase.EndDate)
+		}
+		if phase.Capacity > 0 {
+			fmt.Printf("Capacity: %d\n", phase.Capacity)
+		}
+		fmt.Printf("Created: %s\n", phase.Created)
+		fmt.Printf("Updated: %s\n", phase.Updated)
+		fmt.Printf("Revision: %d\n", phase.Revision)
+
+		fmt.Printf("\n📊 Metrics:\n")
+		fmt.Printf("  Tasks: %d/%d done\n", phase.Metrics.CompletedTasks, phase.Metrics.TotalTasks)
+		fmt.Printf("  Story Points: %d/%d done\n", phase.Metrics.StoryPointsCompleted, phase.Metrics.StoryPointsTotal)
+
+		if len(phaseTasks) > 0 {
+			fmt.Printf("\nTasks:\n")
+			for _, task := range phaseTasks {
+				fmt.Printf("  %s %s - %s (%s)\n", taskStateIcon(task, tasks), task.ID, task.Title, task.Status)
+			}
+		}
+
+		if phase.Notes != "" {
+			fmt.Printf("\nNotes:\n%s\n", phase.Notes)
+		}
+	},
+}
+
+var updatePhaseCmd = &cobra.Command{
+	Use:   "update [phase-id]",
+	Short: "Update phase metadata",
+	Long: `Update Phase Metadata
+
+Updates one or more properties of a phase. To finish a phase use
+'dppm phase close', which checks that no tasks are left open.
+
+Phase Status Values:
+  planning    Phase is being planned
+  active      Phase is currently being worked on
+  cancelled   Phase has been abandoned
+
+Examples:
+  dppm phase update P1 --project web-app --status active --start-date 2026-10-01
+  dppm phase update P2 --project web-app --goal "Ship the public API"
+  dppm phase update P1 --project web-app --capacity 40 --if-revision 2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		phaseID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		phase, err := store.LoadPhase(projectID, phaseID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling phase: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: Phase '%s' not found in project '%s'\n", phaseID, projectID)
+			os.Exit(1)
+		}
+
+		if err := checkIfRevision(cmd, "phase", phaseID, phase.Revision); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		updated := false
+		if cmd.Flags().Changed("status") {
+			status, _ := cmd.Flags().GetString("status")
+			if status == "completed" {
+				fmt.Fprintf(os.Stderr, "Error: Use 'dppm phase close %s --project %s' to complete a phase\n", phaseID, projectID)
+				os.Exit(1)
+			}
+			if !containsString([]string{"planning", "active", "cancelled"}, status) {
+				fmt.Fprintf(os.Stderr, "Error: Invalid status '%s'. Must be one of: planning, active, cancelled\n", status)
+				os.Exit(1)
+			}
+			phase.Status = status
+			updated = true
+		}
+		for _, field := range []struct {
+			flag  string
+			value *string
+		}{
+			{"name", &phase.Name},
+			{"goal", &phase.Goal},
+			{"start-date", &phase.StartDate},
+			{"end-date", &phase.EndDate},
+			{"notes", &phase.Notes},
+		} {
+			if cmd.Flags().Changed(field.flag) {
+				*field.value, _ = cmd.Flags().GetString(field.flag)
+				updated = true
+			}
+		}
+		if cmd.Flags().Changed("capacity") {
+			phase.Capacity, _ = cmd.Flags().GetInt("capacity")
+			updated = true
+		}
+
+		if !updated {
+			fmt.Println("No updates specified. Use --help to see available flags.")
 			return
 		}
 
-		if err := os.WriteFile(phaseFile, data, 0644); err != nil {
+		phase.Updated = nowTimestamp()
+		if err := store.SavePhase(phase); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing phase file: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Phase '%s' updated successfully\n", phaseID)
+	},
+}
+
+var closePhaseCmd = &cobra.Command{
+	Use:   "close [phase-id]",
+	Short: "Complete a phase",
+	Long: `Close a Phase
+
+Marks a phase as completed and stores its final metrics. A phase with open
+tasks (any status other than done) cannot be closed, unless --move-open-to
+names another phase to carry those tasks over to. Moved tasks are renumbered
+like with 'dppm task move'.
+
+Examples:
+  dppm phase close P1 --project web-app
+  dppm phase close P1 --project web-app --move-open-to P2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		phaseID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		moveTo, _ := cmd.Flags().GetString("move-open-to")
+
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		phase, err := store.LoadPhase(projectID, phaseID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Phase '%s' not found in project '%s'\n", phaseID, projectID)
+			os.Exit(1)
+		}
+		if phase.Status == "completed" {
+			fmt.Printf("Phase '%s' is already completed\n", phaseID)
 			return
 		}
 
-		fmt.Printf("Phase '%s' created successfully in project '%s'\n", phaseID, projectID)
-		fmt.Printf("Phase directory: %s\n", phaseDir)
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+
+		if open := completePhase(phase, tasks); len(open) > 0 {
+			if moveTo == "" {
+				fmt.Fprintf(os.Stderr, "Error: Phase '%s' still has %d open task(s):\n", phaseID, len(open))
+				for _, task := range open {
+					fmt.Fprintf(os.Stderr, "  • %s - %s (%s)\n", task.ID, task.Title, task.Status)
+				}
+				fmt.Fprintf(os.Stderr, "Finish them, or move them with: dppm phase close %s --project %s --move-open-to P2\n", phaseID, projectID)
+				os.Exit(1)
+			}
+
+			target, err := store.LoadPhase(projectID, moveTo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Phase '%s' not found in project '%s'\n", moveTo, projectID)
+				os.Exit(1)
+			}
+			if target.ID == phaseID || target.Status == "completed" || target.Status == "cancelled" {
+				fmt.Fprintf(os.Stderr, "Error: Cannot move open tasks to phase '%s' (%s)\n", moveTo, target.Status)
+				os.Exit(1)
+			}
+
+			for _, task := range open {
+				// Subtasks have already moved along with their parent
+				if current, err := store.LoadTask(projectID, task.ID); err == nil && current.PhaseID == moveTo {
+					continue
+				}
+				moved, _, _, err := moveTaskToPhase(projectID, task.ID, moveTo)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				if moved.ID != task.ID {
+					fmt.Printf("📦 Moved %s to %s as %s\n", task.ID, moveTo, moved.ID)
+				} else {
+					fmt.Printf("📦 Moved %s to %s\n", task.ID, moveTo)
+				}
+			}
+
+			if tasks, err = loadProjectTasks(projectID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+				os.Exit(1)
+			}
+			// The moves may have rewritten the phase's tasks list
+			if phase, err = store.LoadPhase(projectID, phaseID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading phase file: %v\n", err)
+				os.Exit(1)
+			}
+			completePhase(phase, tasks)
+		}
+
+		if err := store.SavePhase(phase); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing phase file: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Phase '%s' closed: %d task(s), %d story point(s) completed\n", phaseID, phase.Metrics.CompletedTasks, phase.Metrics.StoryPointsCompleted)
 	},
 }
 
+// loadPhasesWithMetrics returns the project's phases with metrics computed
+// from their tasks, plus all tasks of the project
+func loadPhasesWithMetrics(projectID string) ([]Phase, []Task, error) {
+	phases, err := store.ListPhases(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := loadProjectTasks(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if phases == nil {
+		phases = []Phase{}
+	}
+	for i := range phases {
+		phases[i].Metrics = computePhaseMetrics(tasksInPhase(tasks, phases[i].ID))
+	}
+	return phases, tasks, nil
+}
+
+// completePhase marks a phase as completed with its final metrics. While the
+// phase has open tasks it is left unchanged and those tasks are returned.
+func completePhase(phase *Phase, tasks []Task) []Task {
+	var open []Task
+	for _, task := range tasksInPhase(tasks, phase.ID) {
+		if task.Status != "done" {
+			open = append(open, task)
+		}
+	}
+	if len(open) > 0 {
+		return open
+	}
+
+	phase.Status = "completed"
+	phase.Metrics = computePhaseMetrics(tasksInPhase(tasks, phase.ID))
+	if phase.EndDate == "" {
+		phase.EndDate = time.Now().Format(dateLayout)
+	}
+	phase.Updated = nowTimestamp()
+	return nil
+}
+
+// computePhaseMetrics derives PhaseMetrics from the tasks of one phase
+func computePhaseMetrics(tasks []Task) PhaseMetrics {
+	var metrics PhaseMetrics
+	for _, task := range tasks {
+		metrics.TotalTasks++
+		metrics.StoryPointsTotal += task.StoryPoints
+		if task.Status == "done" {
+			metrics.CompletedTasks++
+			metrics.StoryPointsCompleted += task.StoryPoints
+		}
+	}
+	return metrics
+}
+
+func tasksInPhase(tasks []Task, phaseID string) []Task {
+	var phaseTasks []Task
+	for _, task := range tasks {
+		if task.PhaseID == phaseID {
+			phaseTasks = append(phaseTasks, task)
+		}
+	}
+	return phaseTasks
+}
+
+func countUnphasedTasks(tasks []Task) int {
+	return len(tasksInPhase(tasks, ""))
+}
+
+func phaseStatusIcon(status string) string {
+	switch status {
+	case "completed":
+		return "✅"
+	case "active":
+		return "🔄"
+	case "cancelled":
+		return "❌"
+	default:
+		return "📋"
+	}
+}
+
 func init() {
 	createPhaseCmd.Flags().StringP("name", "n", "", "Phase name")
 	createPhaseCmd.Flags().StringP("project", "p", "", "Project ID (required)")
@@ -182,5 +561,30 @@ func init() {
 
 	createPhaseCmd.MarkFlagRequired("project")
 
+	listPhasesCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	listPhasesCmd.MarkFlagRequired("project")
+
+	showPhaseCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	showPhaseCmd.MarkFlagRequired("project")
+
+	updatePhaseCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	updatePhaseCmd.Flags().StringP("name", "n", "", "Phase name")
+	updatePhaseCmd.Flags().StringP("goal", "g", "", "Phase goal description")
+	updatePhaseCmd.Flags().String("status", "", "Phase status (planning, active, cancelled)")
+	updatePhaseCmd.Flags().String("start-date", "", "Phase start date (YYYY-MM-DD)")
+	updatePhaseCmd.Flags().String("end-date", "", "Phase end date (YYYY-MM-DD)")
+	updatePhaseCmd.Flags().Int("capacity", 0, "Phase capacity (e.g. story points)")
+	updatePhaseCmd.Flags().String("notes", "", "Phase notes")
+	updatePhaseCmd.Flags().Int("if-revision", 0, "Only update if the phase is still at this revision")
+	updatePhaseCmd.MarkFlagRequired("project")
+
+	closePhaseCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	closePhaseCmd.Flags().String("move-open-to", "", "Move open tasks to this phase before closing")
+	closePhaseCmd.MarkFlagRequired("project")
+
 	phaseCmd.AddCommand(createPhaseCmd)
+	phaseCmd.AddCommand(listPhasesCmd)
+	phaseCmd.AddCommand(showPhaseCmd)
+	phaseCmd.AddCommand(updatePhaseCmd)
+	phaseCmd.AddCommand(closePhaseCmd)
 }
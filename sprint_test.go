@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+var phaseTestTasks = []Task{
+	{ID: "T1.1", PhaseID: "P1", Status: "done", StoryPoints: 3},
+	{ID: "T1.2", PhaseID: "P1", Status: "in_progress", StoryPoints: 5},
+	{ID: "T1.3", PhaseID: "P1", Status: "done"},
+	{ID: "T2.1", PhaseID: "P2", Status: "done", StoryPoints: 8},
+	{ID: "setup", Status: "todo", StoryPoints: 1},
+}
+
+func TestComputePhaseMetrics(t *testing.T) {
+	tests := []struct {
+		phaseID string
+		want    PhaseMetrics
+	}{
+		{"P1", PhaseMetrics{CompletedTasks: 2, TotalTasks: 3, StoryPointsCompleted: 3, StoryPointsTotal: 8}},
+		{"P2", PhaseMetrics{CompletedTasks: 1, TotalTasks: 1, StoryPointsCompleted: 8, StoryPointsTotal: 8}},
+		{"P3", PhaseMetrics{}},
+	}
+	for _, test := range tests {
+		if got := computePhaseMetrics(tasksInPhase(phaseTestTasks, test.phaseID)); got != test.want {
+			t.Errorf("%s metrics = %+v, want %+v", test.phaseID, got, test.want)
+		}
+	}
+	if got := countUnphasedTasks(phaseTestTasks); got != 1 {
+		t.Errorf("unphased tasks = %d, want 1", got)
+	}
+}
+
+func TestCompletePhaseRefusesOpenTasks(t *testing.T) {
+	phase := &Phase{ID: "P1", Status: "active"}
+	open := completePhase(phase, phaseTestTasks)
+	if len(open) != 1 || open[0].ID != "T1.2" {
+		t.Errorf("open tasks = %v, want T1.2", open)
+	}
+	if phase.Status != "active" || phase.EndDate != "" || phase.Metrics != (PhaseMetrics{}) {
+		t.Errorf("phase changed although it has open tasks: %+v", phase)
+	}
+}
+
+func TestCompletePhase(t *testing.T) {
+	phase := &Phase{ID: "P2", Status: "active", EndDate: "2026-10-01"}
+	if open := completePhase(phase, phaseTestTasks); len(open) != 0 {
+		t.Fatalf("open tasks = %v", open)
+	}
+	if phase.Status != "completed" || phase.Metrics.CompletedTasks != 1 || phase.Metrics.StoryPointsCompleted != 8 {
+		t.Errorf("closed phase = %+v", phase)
+	}
+	if phase.EndDate != "2026-10-01" {
+		t.Errorf("end date %q overwritten", phase.EndDate)
+	}
+
+	empty := &Phase{ID: "P3"}
+	completePhase(empty, phaseTestTasks)
+	if empty.Status != "completed" || empty.EndDate == "" {
+		t.Errorf("empty phase closed as %+v", empty)
+	}
+}
+
+func TestLoadPhasesWithMetrics(t *testing.T) {
+	store = NewMemoryStore()
+	for _, phaseID := range []string{"P1", "P2"} {
+		if err := store.SavePhase(&Phase{ID: phaseID, ProjectID: "demo"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, task := range phaseTestTasks {
+		task.ProjectID = "demo"
+		if err := store.SaveTask(&task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	phases, tasks, err := loadPhasesWithMetrics("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phases) != 2 || len(tasks) != len(phaseTestTasks) {
+		t.Fatalf("%d phases, %d tasks", len(phases), len(tasks))
+	}
+	for _, phase := range phases {
+		if want := computePhaseMetrics(tasksInPhase(phaseTestTasks, phase.ID)); phase.Metrics != want {
+			t.Errorf("%s metrics = %+v, want %+v", phase.ID, phase.Metrics, want)
+		}
+	}
+}
@@ -3,11 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var statusCmd = &cobra.Command{
@@ -24,12 +22,21 @@ Available Subcommands:
   dependencies Show all dependency chains
   blocked      Show tasks blocked by dependencies
   active       Show tasks that can be worked on now
+  order        Show tasks in dependency order with the critical path
 
 Examples:
   dppm status project dash-lxd
   dppm status dependencies
   dppm status blocked
-  dppm status active --project dash-lxd`,
+  dppm status active --project dash-lxd
+  dppm status order --project dash-lxd`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		if cmd == statusProjectCmd && len(args) > 0 {
+			projectID = args[0]
+		}
+		warnAboutConflicts(projectID)
+	},
 }
 
 var statusProjectCmd = &cobra.Command{
@@ -39,9 +46,6 @@ var statusProjectCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		projectID := args[0]
 
-		fmt.Printf("Project Status: %s\n", projectID)
-		fmt.Println("=====================")
-
 		// Load all tasks for project
 		tasks, err := loadProjectTasks(projectID)
 		if err != nil {
@@ -49,49 +53,31 @@ var statusProjectCmd = &cobra.Command{
 			return
 		}
 
-		// Calculate statistics
-		todoCount := 0
-		inProgressCount := 0
-		doneCount := 0
-		blockedCount := 0
-
-		for _, task := range tasks {
-			switch task.Status {
-			case "todo":
-				if isTaskBlocked(task, tasks) {
-					blockedCount++
-				} else {
-					todoCount++
-				}
-			case "in_progress":
-				inProgressCount++
-			case "done":
-				doneCount++
-			}
+		status := buildProjectStatus(projectID, tasks)
+		if printStructured(status) {
+			return
 		}
 
-		fmt.Printf("Total Tasks: %d\n", len(tasks))
-		fmt.Printf("✅ Done: %d\n", doneCount)
-		fmt.Printf("🔄 In Progress: %d\n", inProgressCount)
-		fmt.Printf("📋 Ready to Start: %d\n", todoCount)
-		fmt.Printf("🚫 Blocked: %d\n", blockedCount)
+		fmt.Printf("Project Status: %s\n", projectID)
+		fmt.Println("=====================")
+
+		fmt.Printf("Total Tasks: %d\n", status.Counts.Total)
+		fmt.Printf("✅ Done: %d\n", status.Counts.Done)
+		fmt.Printf("🔄 In Progress: %d\n", status.Counts.InProgress)
+		fmt.Printf("📋 Ready to Start: %d\n", status.Counts.Ready)
+		fmt.Printf("🚫 Blocked: %d\n", status.Counts.Blocked)
 
-		if blockedCount > 0 {
+		if len(status.Blocked) > 0 {
 			fmt.Println("\n🚫 Blocked Tasks:")
-			for _, task := range tasks {
-				if task.Status == "todo" && isTaskBlocked(task, tasks) {
-					blockers := getBlockingTasks(task, tasks)
-					fmt.Printf("  • %s (blocked by: %s)\n", task.Title, strings.Join(blockers, ", "))
-				}
+			for _, task := range status.Blocked {
+				fmt.Printf("  • %s (blocked by: %s)\n", task.Title, strings.Join(refTitles(task.BlockedBy), ", "))
 			}
 		}
 
-		if todoCount > 0 {
+		if len(status.Ready) > 0 {
 			fmt.Println("\n📋 Ready to Work On:")
-			for _, task := range tasks {
-				if task.Status == "todo" && !isTaskBlocked(task, tasks) {
-					fmt.Printf("  • %s (%s priority)\n", task.Title, task.Priority)
-				}
+			for _, task := range status.Ready {
+				fmt.Printf("  • %s (%s priority)\n", task.Title, task.Priority)
 			}
 		}
 	},
@@ -115,78 +101,115 @@ Shows which tasks are blocking each blocked task.`,
 	},
 }
 
-var statusDependenciesCmd = &cobra.Command{
-	Use:   "dependencies",
-	Short: "Show all dependency chains",
-	Long: `Show Dependency Chains
+var statusActiveCmd = &cobra.Command{
+	Use:   "active",
+	Short: "Show tasks that can be worked on now",
+	Long: `Show Active Tasks
 
-Display comprehensive view of all task dependencies across projects.
-Shows the dependency graph and highlights potential issues.`,
+Lists the tasks that can be worked on now: tasks in progress and todo tasks
+whose dependencies are all done. Without --project every project is shown.
+
+Examples:
+  dppm status active
+  dppm status active --project dash-lxd
+  dppm status active --project dash-lxd --output json`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		projectID, _ := cmd.Flags().GetString("project")
 
-		if projectID != "" {
-			showDependenciesForProject(projectID)
-		} else {
-			showAllDependencies()
+		projectIDs := []string{projectID}
+		if projectID == "" {
+			var err error
+			if projectIDs, err = allProjectIDs(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
+				os.Exit(1)
+			}
 		}
-	},
-}
 
-func loadProjectTasks(projectID string) ([]Task, error) {
-	var tasks []Task
-
-	// Load tasks from phases directories
-	phasesDir := filepath.Join(projectsPath, "projects", projectID, "phases")
-	phaseEntries, err := os.ReadDir(phasesDir)
-	if err == nil {
-		for _, phaseEntry := range phaseEntries {
-			if phaseEntry.IsDir() {
-				tasksDir := filepath.Join(phasesDir, phaseEntry.Name(), "tasks")
-				if phaseTasks, err := loadTasksFromDir(tasksDir); err == nil {
-					tasks = append(tasks, phaseTasks...)
+		active, err := collectActiveTasks(projectIDs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(active) {
+			return
+		}
+
+		fmt.Println("Active Tasks:")
+		fmt.Println("=============")
+
+		if len(active) == 0 {
+			fmt.Println("No tasks can be worked on right now.")
+			return
+		}
+		for _, projectID := range projectIDs {
+			printed := false
+			for _, task := range active {
+				if task.ProjectID != projectID {
+					continue
+				}
+				if !printed {
+					fmt.Printf("\n%s:\n", projectID)
+					printed = true
 				}
+				icon := "📋"
+				if task.Status == "in_progress" {
+					icon = "🔄"
+				}
+				fmt.Printf("  %s %s - %s (%s priority)\n", icon, task.ID, task.Title, task.Priority)
 			}
 		}
-	}
+	},
+}
 
-	// Also check for tasks directly in project directory (for backwards compatibility)
-	tasksDir := filepath.Join(projectsPath, "projects", projectID, "tasks")
+var statusDependenciesCmd = &cobra.Command{
+	Use:   "dependencies",
+	Short: "Show all dependency chains",
+	Long: `Show Dependency Chains
 
-	if directTasks, err := loadTasksFromDir(tasksDir); err == nil {
-		tasks = append(tasks, directTasks...)
-	}
+Display comprehensive view of all task dependencies across projects.
+Shows the dependency graph and highlights potential issues.
 
-	return tasks, nil
-}
+Output Formats (--format):
+  text       Human-readable dependency chains (default)
+  dot        Graphviz DOT, render with: dot -Tsvg deps.dot -o deps.svg
+  mermaid    Mermaid flowchart, paste into markdown design docs
 
-func loadTasksFromDir(tasksDir string) ([]Task, error) {
-	var tasks []Task
+In graph formats tasks are grouped by phase, coloured by status, and tasks
+blocked by unfinished dependencies get a red border.
 
-	entries, err := os.ReadDir(tasksDir)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
-			taskFile := filepath.Join(tasksDir, entry.Name())
+Examples:
+  dppm status dependencies --project web-app
+  dppm status dependencies --project web-app --format dot > deps.dot
+  dppm status dependencies --project web-app --format mermaid`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		format, _ := cmd.Flags().GetString("format")
 
-			data, err := os.ReadFile(taskFile)
-			if err != nil {
-				continue
-			}
+		if format == "dot" || format == "mermaid" {
+			showDependencyGraph(projectID, format)
+			return
+		}
+		if format != "" && format != "text" {
+			fmt.Fprintf(os.Stderr, "Error: Invalid format '%s'. Must be one of: text, dot, mermaid\n", format)
+			os.Exit(1)
+		}
 
-			var task Task
-			if err := yaml.Unmarshal(data, &task); err != nil {
-				continue
-			}
+		if structuredOutput() {
+			showDependencyReports(projectID)
+			return
+		}
 
-			tasks = append(tasks, task)
+		if projectID != "" {
+			showDependenciesForProject(projectID)
+		} else {
+			showAllDependencies()
 		}
-	}
+	},
+}
 
-	return tasks, nil
+func loadProjectTasks(projectID string) ([]Task, error) {
+	return store.ListTasks(projectID)
 }
 
 func isTaskBlocked(task Task, allTasks []Task) bool {
@@ -229,50 +252,116 @@ func getBlockingTasks(task Task, allTasks []Task) []string {
 	return blockers
 }
 
+// collectBlockedTasks returns the blocked todo tasks of the given projects
+func collectBlockedTasks(projectIDs []string) ([]BlockedTask, error) {
+	blocked := []BlockedTask{}
+	for _, projectID := range projectIDs {
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, buildProjectStatus(projectID, tasks).Blocked...)
+	}
+	return blocked, nil
+}
+
 func showBlockedTasksForProject(projectID string) {
-	tasks, err := loadProjectTasks(projectID)
+	blocked, err := collectBlockedTasks([]string{projectID})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
 		return
 	}
+	if printStructured(blocked) {
+		return
+	}
+
+	printBlockedTasks(projectID, blocked)
+}
 
+func printBlockedTasks(projectID string, blocked []BlockedTask) {
 	fmt.Printf("Blocked Tasks in %s:\n", projectID)
 	fmt.Println("========================")
 
-	hasBlocked := false
-	for _, task := range tasks {
-		if task.Status == "todo" && isTaskBlocked(task, tasks) {
-			hasBlocked = true
-			blockers := getBlockingTasks(task, tasks)
-			fmt.Printf("🚫 %s\n", task.Title)
-			fmt.Printf("   Priority: %s\n", task.Priority)
-			fmt.Printf("   Blocked by: %s\n", strings.Join(blockers, ", "))
-			fmt.Println()
-		}
+	for _, task := range blocked {
+		fmt.Printf("🚫 %s\n", task.Title)
+		fmt.Printf("   Priority: %s\n", task.Priority)
+		fmt.Printf("   Blocked by: %s\n", strings.Join(refTitles(task.BlockedBy), ", "))
+		fmt.Println()
 	}
 
-	if !hasBlocked {
+	if len(blocked) == 0 {
 		fmt.Println("✅ No blocked tasks! All tasks are ready to work on.")
 	}
 }
 
 func showAllBlockedTasks() {
-	projectsDir := filepath.Join(projectsPath, "projects")
-
-	entries, err := os.ReadDir(projectsDir)
+	projectIDs, err := allProjectIDs()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
 		return
 	}
 
+	blocked, err := collectBlockedTasks(projectIDs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return
+	}
+	if printStructured(blocked) {
+		return
+	}
+
 	fmt.Println("All Blocked Tasks:")
 	fmt.Println("==================")
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			showBlockedTasksForProject(entry.Name())
+	for _, projectID := range projectIDs {
+		var projectBlocked []BlockedTask
+		for _, task := range blocked {
+			if task.ProjectID == projectID {
+				projectBlocked = append(projectBlocked, task)
+			}
+		}
+		printBlockedTasks(projectID, projectBlocked)
+	}
+}
+
+// collectActiveTasks returns the in-progress tasks and the unblocked todo
+// tasks of the given projects
+func collectActiveTasks(projectIDs []string) ([]TaskRef, error) {
+	active := []TaskRef{}
+	for _, projectID := range projectIDs {
+		tasks, err := loadProjectTasks(projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			if task.Status == "in_progress" || (task.Status == "todo" && !isTaskBlocked(task, tasks)) {
+				active = append(active, taskRef(task))
+			}
 		}
 	}
+	return active, nil
+}
+
+// allProjectIDs lists the IDs of every project in the store
+func allProjectIDs() ([]string, error) {
+	projects, err := store.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	var projectIDs []string
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	return projectIDs, nil
+}
+
+// refTitles returns the titles of the referenced tasks
+func refTitles(refs []TaskRef) []string {
+	var titles []string
+	for _, ref := range refs {
+		titles = append(titles, ref.Title)
+	}
+	return titles
 }
 
 func showDependenciesForProject(projectID string) {
@@ -307,27 +396,81 @@ func showDependenciesForProject(projectID string) {
 	}
 }
 
-func showAllDependencies() {
-	projectsDir := filepath.Join(projectsPath, "projects")
+// showDependencyReports prints every task with dependencies as JSON or YAML
+func showDependencyReports(projectID string) {
+	projectIDs := []string{projectID}
+	if projectID == "" {
+		var err error
+		if projectIDs, err = allProjectIDs(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	entries, err := os.ReadDir(projectsDir)
+	reports := []DependencyReport{}
+	for _, id := range projectIDs {
+		tasks, err := loadProjectTasks(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+		for _, task := range tasks {
+			if len(task.DependencyIDs) > 0 {
+				reports = append(reports, buildDependencyReport(task, tasks))
+			}
+		}
+	}
+
+	printStructured(reports)
+}
+
+func showDependencyGraph(projectID, format string) {
+	projectIDs := []string{projectID}
+	if projectID == "" {
+		var err error
+		if projectIDs, err = allProjectIDs(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var graph []graphProject
+	for _, id := range projectIDs {
+		tasks, err := loadProjectTasks(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			os.Exit(1)
+		}
+		graph = append(graph, graphProject{ID: id, Tasks: tasks})
+	}
+
+	if format == "dot" {
+		fmt.Print(renderDependencyDOT(graph))
+	} else {
+		fmt.Print(renderDependencyMermaid(graph))
+	}
+}
+
+func showAllDependencies() {
+	projects, err := store.ListProjects()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
 		return
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			showDependenciesForProject(entry.Name())
-		}
+	for _, project := range projects {
+		showDependenciesForProject(project.ID)
 	}
 }
 
 func init() {
 	statusBlockedCmd.Flags().StringP("project", "p", "", "Show blocked tasks for specific project")
+	statusActiveCmd.Flags().StringP("project", "p", "", "Show active tasks for specific project")
 	statusDependenciesCmd.Flags().StringP("project", "p", "", "Show dependencies for specific project")
+	statusDependenciesCmd.Flags().String("format", "text", "Output format (text, dot, mermaid)")
 
 	statusCmd.AddCommand(statusProjectCmd)
 	statusCmd.AddCommand(statusBlockedCmd)
+	statusCmd.AddCommand(statusActiveCmd)
 	statusCmd.AddCommand(statusDependenciesCmd)
 }
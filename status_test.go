@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectActiveTasks(t *testing.T) {
+	store = NewMemoryStore()
+	for _, task := range []Task{
+		{ID: "T1.1", ProjectID: "web", Status: "done"},
+		{ID: "T1.2", ProjectID: "web", Status: "in_progress"},
+		{ID: "T1.3", ProjectID: "web", Status: "todo", DependencyIDs: []string{"T1.1"}},
+		{ID: "T1.4", ProjectID: "web", Status: "todo", DependencyIDs: []string{"T1.2"}},
+		{ID: "T1.5", ProjectID: "web", Status: "review"},
+		{ID: "T1.1", ProjectID: "api", Status: "todo"},
+	} {
+		task := task
+		if err := store.SaveTask(&task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	active, err := collectActiveTasks([]string{"web", "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, task := range active {
+		got = append(got, task.ProjectID+"/"+task.ID)
+	}
+	if want := []string{"web/T1.2", "web/T1.3", "api/T1.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("active tasks %v, want %v", got, want)
+	}
+}
+
+func TestAdvertisedSubcommandsExist(t *testing.T) {
+	for _, path := range [][]string{{"list", "phases"}, {"status", "active"}} {
+		cmd, _, err := rootCmd.Find(path)
+		if err != nil || cmd.Name() != path[1] {
+			t.Errorf("dppm %s %s is not registered: %v", path[0], path[1], err)
+			continue
+		}
+		if cmd.Flag("project") == nil || cmd.Flag("output") == nil {
+			t.Errorf("dppm %s %s lacks --project or --output", path[0], path[1])
+		}
+	}
+}
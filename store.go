@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by a Store when a project, phase or task does not exist
+var ErrNotFound = errors.New("not found")
+
+// Store abstracts how projects, phases and tasks are persisted. Every command
+// goes through the active store instead of touching YAML files directly, so new
+// backends can be added without changing the cobra handlers.
+//
+// Save methods are compare-and-swap: they fail with ErrRevisionConflict when
+// the stored revision differs from the revision of the value being saved, and
+// on success they increment the revision of the saved value.
+type Store interface {
+	// Projects
+	ListProjects() ([]Project, error)
+	ProjectExists(projectID string) (bool, error)
+	LoadProject(projectID string) (*Project, error)
+	SaveProject(project *Project) error
+
+	// Phases
+	ListPhases(projectID string) ([]Phase, error)
+	PhaseExists(projectID, phaseID string) (bool, error)
+	LoadPhase(projectID, phaseID string) (*Phase, error)
+	SavePhase(phase *Phase) error
+
+	// Tasks
+	ListTasks(projectID string) ([]Task, error)
+	// LoadTask also resolves former task IDs recorded in Task.Aliases
+	LoadTask(projectID, taskID string) (*Task, error)
+	SaveTask(task *Task) error
+	DeleteTask(projectID, taskID string) error
+	// MoveTask saves a task that was stored as oldID, possibly in another
+	// phase, and removes the old copy. The revision check applies to the old copy.
+	MoveTask(oldID string, task *Task) error
+
+	// Lock takes an exclusive lock on a project for a read-modify-write cycle.
+	// The returned function releases it.
+	Lock(projectID string) (func(), error)
+}
+
+// store is the active storage backend, set up in main after Dropbox validation
+var store Store
+
+// isNotFound reports whether err means the requested entity does not exist
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// notFoundError builds an ErrNotFound wrapping error with a readable message
+func notFoundError(kind, id string) error {
+	return fmt.Errorf("%s '%s' %w", kind, id, ErrNotFound)
+}
+
+// findTaskByAlias returns the task that was formerly known as taskID
+func findTaskByAlias(tasks []Task, taskID string) *Task {
+	for i := range tasks {
+		if containsString(tasks[i].Aliases, taskID) {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// findTask searches all projects for a task with the given ID
+func findTask(s Store, taskID string) (*Task, error) {
+	projects, err := s.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		task, err := s.LoadTask(project.ID, taskID)
+		if err == nil {
+			return task, nil
+		}
+		if !isNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return nil, notFoundError("task", taskID)
+}
@@ -0,0 +1,672 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// conflictedCopyRegex matches Dropbox conflict files such as
+// "T1.1 (John's conflicted copy 2026-10-01).yaml" or
+// "project (conflicted copy 2026-10-01 (1)).yaml"
+var conflictedCopyRegex = regexp.MustCompile(`^(.+?) \(([^()]*?)conflicted copy [^()]*?(?:\(\d+\))?\)(\.[A-Za-z0-9]+)$`)
+
+// ConflictedCopy is a Dropbox conflict file next to its canonical file
+type ConflictedCopy struct {
+	Path          string // the conflicted copy
+	CanonicalPath string // the file it was forked from
+	Owner         string // whose machine produced the copy, if Dropbox recorded it
+}
+
+// Conflict resolution strategies
+const (
+	strategyTakeMine   = "take-mine"
+	strategyTakeTheirs = "take-theirs"
+	strategyMerge      = "merge"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Dropbox synchronization tools",
+	Long: `Dropbox Synchronization Tools
+
+DPPM stores everything as YAML files in Dropbox. When two machines edit the
+same file before Dropbox has synced, Dropbox keeps both versions and renames
+one of them to a "conflicted copy". These commands find and resolve them.
+
+Commands that work on one project (list, status, query, report and collab
+sync with --project) warn when that project has conflicted copies.
+
+Available Subcommands:
+  conflicts    Find conflicted copies and resolve them
+
+Examples:
+  dppm sync conflicts
+  dppm sync conflicts --project web-app
+  dppm sync conflicts --resolve merge`,
+}
+
+var syncConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Find and resolve Dropbox conflicted copies",
+	Long: `Find and Resolve Dropbox Conflicted Copies
+
+Scans the project tree for files like:
+  T1.1 (John's conflicted copy 2026-10-01).yaml
+
+For each conflict a field-level diff against the canonical file is shown.
+"mine" is the canonical file (T1.1.yaml), "theirs" is the conflicted copy.
+
+Resolution Strategies (--resolve):
+  take-mine      Keep the canonical file, delete the conflicted copy
+  take-theirs    Replace the canonical file with the conflicted copy
+  merge          Three-way field merge. The history both versions share is
+                 their common ancestor: a field changed on only one side
+                 since then takes that side's value, and ID lists such as
+                 dependency_ids keep removals made on either side.
+                 Components and issues are merged by ID, time logs and
+                 comments are combined, and the revision is bumped past
+                 both. Fields both sides changed take the value from the
+                 version with the newest 'updated' timestamp
+
+Without --resolve the conflicts are only reported.
+
+Examples:
+  dppm sync conflicts                           # Report all conflicts
+  dppm sync conflicts --project web-app         # Only one project
+  dppm sync conflicts --resolve merge           # Merge every conflict
+  dppm sync conflicts --resolve take-theirs --project web-app`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		strategy, _ := cmd.Flags().GetString("resolve")
+
+		if strategy != "" && strategy != strategyTakeMine && strategy != strategyTakeTheirs && strategy != strategyMerge {
+			fmt.Fprintf(os.Stderr, "Error: Invalid strategy '%s'. Must be one of: %s, %s, %s\n",
+				strategy, strategyTakeMine, strategyTakeTheirs, strategyMerge)
+			os.Exit(1)
+		}
+
+		conflicts, err := findConflictedCopies(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning for conflicts: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Dropbox Conflicted Copies:")
+		fmt.Println("==========================")
+
+		if len(conflicts) == 0 {
+			fmt.Println("✅ No conflicted copies found.")
+			return
+		}
+
+		root := filepath.Join(projectsPath, "projects")
+		resolved := 0
+		for _, conflict := range conflicts {
+			rel, _ := filepath.Rel(root, conflict.CanonicalPath)
+			fmt.Printf("⚠️  %s\n", rel)
+			fmt.Printf("   Conflicted copy: %s\n", filepath.Base(conflict.Path))
+			if conflict.Owner != "" {
+				fmt.Printf("   From: %s\n", conflict.Owner)
+			}
+
+			diffs, err := diffConflict(conflict)
+			if err != nil {
+				fmt.Printf("   ❌ Cannot compare: %v\n\n", err)
+				continue
+			}
+			if len(diffs) == 0 {
+				fmt.Println("   (identical content)")
+			}
+			for _, line := range diffs {
+				fmt.Printf("   %s\n", line)
+			}
+
+			if strategy != "" {
+				unlock, err := lockForPath(conflict.CanonicalPath)
+				if err != nil {
+					fmt.Printf("   ❌ %v\n\n", err)
+					continue
+				}
+				err = resolveConflict(conflict, strategy)
+				unlock()
+				if err != nil {
+					fmt.Printf("   ❌ Failed to resolve: %v\n\n", err)
+					continue
+				}
+				fmt.Printf("   ✅ Resolved with %s\n", strategy)
+				resolved++
+			}
+			fmt.Println()
+		}
+
+		fmt.Printf("Found %d conflicted copies", len(conflicts))
+		if strategy != "" {
+			fmt.Printf(", resolved %d", resolved)
+		}
+		fmt.Println()
+
+		if strategy == "" {
+			fmt.Println("\n💡 Resolve with:")
+			fmt.Println("   dppm sync conflicts --resolve take-mine    # Keep canonical files")
+			fmt.Println("   dppm sync conflicts --resolve take-theirs  # Use conflicted copies")
+			fmt.Println("   dppm sync conflicts --resolve merge        # Field-level merge")
+		}
+	},
+}
+
+// isConflictedCopy reports whether a file name is a Dropbox conflicted copy
+func isConflictedCopy(name string) bool {
+	return conflictedCopyRegex.MatchString(name)
+}
+
+// findConflictedCopies walks the projects tree (or one project) for conflict files
+func findConflictedCopies(projectID string) ([]ConflictedCopy, error) {
+	root := filepath.Join(projectsPath, "projects")
+	if projectID != "" {
+		root = filepath.Join(root, projectID)
+	}
+
+	var conflicts []ConflictedCopy
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		matches := conflictedCopyRegex.FindStringSubmatch(info.Name())
+		if matches == nil {
+			return nil
+		}
+
+		conflicts = append(conflicts, ConflictedCopy{
+			Path:          path,
+			CanonicalPath: filepath.Join(filepath.Dir(path), matches[1]+matches[3]),
+			Owner:         strings.TrimSuffix(strings.TrimSpace(matches[2]), "'s"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conflicts, nil
+}
+
+// warnAboutConflicts prints a one-line warning when the project a command
+// works on has conflicted copies. Commands spanning all projects are not
+// checked, as that would walk the whole projects tree on every run.
+func warnAboutConflicts(projectID string) {
+	if projectsPath == "" || projectID == "" {
+		return
+	}
+	conflicts, err := findConflictedCopies(projectID)
+	if err != nil || len(conflicts) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  %d Dropbox conflicted copies found in project '%s' - run: dppm sync conflicts --project %s\n\n", len(conflicts), projectID, projectID)
+}
+
+// diffConflict returns one line per top-level field that differs
+func diffConflict(conflict ConflictedCopy) ([]string, error) {
+	mine, theirs, err := loadConflictPair(conflict)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for _, key := range unionKeys(mine, theirs) {
+		mineValue, inMine := mine[key]
+		theirValue, inTheirs := theirs[key]
+		if inMine && inTheirs && reflect.DeepEqual(mineValue, theirValue) {
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: mine=%s theirs=%s", key, formatFieldValue(mineValue, inMine), formatFieldValue(theirValue, inTheirs)))
+	}
+
+	return diffs, nil
+}
+
+// resolveConflict applies a strategy and removes the conflicted copy
+func resolveConflict(conflict ConflictedCopy, strategy string) error {
+	switch strategy {
+	case strategyTakeMine:
+		if _, err := os.Stat(conflict.CanonicalPath); err != nil {
+			return fmt.Errorf("canonical file missing, use take-theirs instead")
+		}
+
+	case strategyTakeTheirs:
+		data, err := os.ReadFile(conflict.Path)
+		if err != nil {
+			return err
+		}
+		var theirs map[string]interface{}
+		if err := yaml.Unmarshal(data, &theirs); err != nil {
+			return fmt.Errorf("conflicted copy is not valid YAML: %v", err)
+		}
+		if err := writeYAMLFile(conflict.CanonicalPath, typedEntity(conflict.CanonicalPath, theirs)); err != nil {
+			return err
+		}
+
+	case strategyMerge:
+		mine, theirs, err := loadConflictPair(conflict)
+		if err != nil {
+			return err
+		}
+		entity := typedEntity(conflict.CanonicalPath, mergeConflictFields(mine, theirs))
+		if task, ok := entity.(*Task); ok {
+			rollupActualHours(task)
+		}
+		if err := writeYAMLFile(conflict.CanonicalPath, entity); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(conflict.Path)
+}
+
+// mergeConflictFields merges two versions of the same entity field by field.
+// The shared start of both history logs is the common ancestor: a field that
+// only one side changed since then takes that side's value, and list fields
+// such as dependency_ids are merged against the ancestor's value so removals
+// survive. Lists of objects with an 'id' are merged item by item, time logs,
+// comments and history are combined, and the revision is bumped past both
+// sides. Any other differing field takes the value of the version with the
+// newest 'updated' timestamp (mine wins ties).
+func mergeConflictFields(mine, theirs map[string]interface{}) map[string]interface{} {
+	base, mineHistory, theirHistory := splitHistory(historyOf(mine), historyOf(theirs))
+	m := conflictMerge{
+		theirsNewer:  parseTimestamp(fmt.Sprint(theirs["updated"])).After(parseTimestamp(fmt.Sprint(mine["updated"]))),
+		mineChanges:  changedFields(mineHistory),
+		theirChanges: changedFields(theirHistory),
+	}
+
+	merged := make(map[string]interface{})
+	for _, key := range unionKeys(mine, theirs) {
+		mineValue, inMine := mine[key]
+		theirValue, inTheirs := theirs[key]
+
+		switch {
+		case !inTheirs:
+			merged[key] = mineValue
+		case !inMine:
+			merged[key] = theirValue
+		case reflect.DeepEqual(mineValue, theirValue):
+			merged[key] = mineValue
+		case key == "history":
+			merged[key] = mergeHistory(base, mineHistory, theirHistory)
+		default:
+			merged[key] = m.value(key, mineValue, theirValue)
+		}
+	}
+	// Neither side's revision describes the merged file
+	merged["revision"] = maxInt(toInt(mine["revision"]), toInt(theirs["revision"])) + 1
+
+	return merged
+}
+
+// conflictMerge holds what is known about both sides of a conflict
+type conflictMerge struct {
+	theirsNewer bool
+	// History entries recorded on each side since the common ancestor, by field
+	mineChanges  map[string][]HistoryEntry
+	theirChanges map[string][]HistoryEntry
+}
+
+// value merges one differing field; field is its dotted path, e.g. "components.C1"
+func (m conflictMerge) value(field string, mineValue, theirValue interface{}) interface{} {
+	mineMap, mineIsMap := mineValue.(map[string]interface{})
+	theirMap, theirIsMap := theirValue.(map[string]interface{})
+	if mineIsMap && theirIsMap {
+		return m.object(field, mineMap, theirMap)
+	}
+
+	mineList, mineIsList := mineValue.([]interface{})
+	theirList, theirIsList := theirValue.([]interface{})
+	if mineIsList && theirIsList {
+		switch {
+		case field == "time_tracking.time_logs" || field == "comments":
+			return unionList(mineList, theirList)
+		case hasIDs(mineList) && hasIDs(theirList):
+			return m.listByID(field, mineList, theirList)
+		case isStringList(mineList) && isStringList(theirList):
+			return m.stringList(field, mineList, theirList)
+		}
+		return unionList(mineList, theirList)
+	}
+
+	return m.pick(field, mineValue, theirValue)
+}
+
+// object merges two maps key by key
+func (m conflictMerge) object(field string, mine, theirs map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, key := range unionKeys(mine, theirs) {
+		mineValue, inMine := mine[key]
+		theirValue, inTheirs := theirs[key]
+		switch {
+		case !inTheirs:
+			merged[key] = mineValue
+		case !inMine:
+			merged[key] = theirValue
+		case reflect.DeepEqual(mineValue, theirValue):
+			merged[key] = mineValue
+		default:
+			merged[key] = m.value(field+"."+key, mineValue, theirValue)
+		}
+	}
+	return merged
+}
+
+// listByID merges lists of objects such as components and issues by their id.
+// Items keep mine's order, followed by items only theirs has.
+func (m conflictMerge) listByID(field string, mine, theirs []interface{}) []interface{} {
+	theirByID := make(map[string]map[string]interface{})
+	for _, item := range theirs {
+		object := item.(map[string]interface{})
+		theirByID[fmt.Sprint(object["id"])] = object
+	}
+
+	merged := make([]interface{}, 0, len(mine)+len(theirs))
+	seen := make(map[string]bool)
+	for _, item := range mine {
+		object := item.(map[string]interface{})
+		id := fmt.Sprint(object["id"])
+		seen[id] = true
+		if their, ok := theirByID[id]; ok && !reflect.DeepEqual(object, their) {
+			merged = append(merged, m.object(field+"."+id, object, their))
+			continue
+		}
+		merged = append(merged, object)
+	}
+	for _, item := range theirs {
+		if id := fmt.Sprint(item.(map[string]interface{})["id"]); !seen[id] {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// stringList merges ID and label lists against the common ancestor: items one
+// side removed stay removed, items either side added are kept. Without a
+// recorded ancestor the lists are combined.
+func (m conflictMerge) stringList(field string, mine, theirs []interface{}) []interface{} {
+	mineChanged, theirChanged := len(m.mineChanges[field]) > 0, len(m.theirChanges[field]) > 0
+	switch {
+	case mineChanged && !theirChanged:
+		return mine
+	case theirChanged && !mineChanged:
+		return theirs
+	case !mineChanged && !theirChanged:
+		return unionList(mine, theirs)
+	}
+
+	base := splitHistoryList(m.mineChanges[field][0].From)
+	var merged []interface{}
+	for _, item := range unionList(mine, theirs) {
+		inBase := containsString(base, fmt.Sprint(item))
+		inMine, inTheirs := containsValue(mine, item), containsValue(theirs, item)
+		if inBase && (!inMine || !inTheirs) {
+			continue // removed on one side
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// pick chooses between two differing values: the side that changed the field
+// since the common ancestor wins, otherwise the newest version
+func (m conflictMerge) pick(field string, mineValue, theirValue interface{}) interface{} {
+	mineChanged, theirChanged := m.changed(m.mineChanges, field), m.changed(m.theirChanges, field)
+	switch {
+	case mineChanged && !theirChanged:
+		return mineValue
+	case theirChanged && !mineChanged:
+		return theirValue
+	case m.theirsNewer:
+		return theirValue
+	}
+	return mineValue
+}
+
+// changed reports whether a side recorded a change of field or of the object
+// containing it, e.g. "issues.T1.1.B1" for "issues.T1.1.B1.status"
+func (m conflictMerge) changed(changes map[string][]HistoryEntry, field string) bool {
+	for ; field != ""; field = parentField(field) {
+		if len(changes[field]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func parentField(field string) string {
+	if i := strings.LastIndexByte(field, '.'); i >= 0 {
+		return field[:i]
+	}
+	return ""
+}
+
+// historyOf decodes the history section of a generic YAML map
+func historyOf(fields map[string]interface{}) []HistoryEntry {
+	var history []HistoryEntry
+	if raw, ok := fields["history"]; ok {
+		if data, err := yaml.Marshal(raw); err == nil {
+			yaml.Unmarshal(data, &history)
+		}
+	}
+	return history
+}
+
+// splitHistory returns the entries both logs share and what each side added since
+func splitHistory(mine, theirs []HistoryEntry) (base, mineOnly, theirOnly []HistoryEntry) {
+	n := 0
+	for n < len(mine) && n < len(theirs) && mine[n] == theirs[n] {
+		n++
+	}
+	return mine[:n], mine[n:], theirs[n:]
+}
+
+// changedFields groups history entries by field, oldest first
+func changedFields(history []HistoryEntry) map[string][]HistoryEntry {
+	changes := make(map[string][]HistoryEntry)
+	for _, entry := range history {
+		changes[entry.Field] = append(changes[entry.Field], entry)
+	}
+	return changes
+}
+
+// mergeHistory appends the entries of both sides after the shared ones, in time order
+func mergeHistory(base, mine, theirs []HistoryEntry) []HistoryEntry {
+	added := append(append([]HistoryEntry{}, mine...), theirs...)
+	sort.SliceStable(added, func(i, j int) bool {
+		return parseTimestamp(added[i].Timestamp).Before(parseTimestamp(added[j].Timestamp))
+	})
+	return append(append([]HistoryEntry{}, base...), added...)
+}
+
+// splitHistoryList reads a list recorded in history as "T1.1, T1.2"
+func splitHistoryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func hasIDs(list []interface{}) bool {
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok || object["id"] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isStringList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// unionList appends the items of b that are not already in a
+func unionList(a, b []interface{}) []interface{} {
+	result := append([]interface{}{}, a...)
+	for _, item := range b {
+		found := false
+		for _, existing := range result {
+			if reflect.DeepEqual(existing, item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func loadConflictPair(conflict ConflictedCopy) (map[string]interface{}, map[string]interface{}, error) {
+	mine := map[string]interface{}{}
+	if data, err := os.ReadFile(conflict.CanonicalPath); err == nil {
+		if err := yaml.Unmarshal(data, &mine); err != nil {
+			return nil, nil, fmt.Errorf("canonical file is not valid YAML: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	theirs := map[string]interface{}{}
+	data, err := os.ReadFile(conflict.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := yaml.Unmarshal(data, &theirs); err != nil {
+		return nil, nil, fmt.Errorf("conflicted copy is not valid YAML: %v", err)
+	}
+
+	return mine, theirs, nil
+}
+
+// typedEntity converts a generic YAML map back into the struct matching the
+// file, so the written file keeps the usual field order
+func typedEntity(path string, fields map[string]interface{}) interface{} {
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return fields
+	}
+
+	var entity interface{}
+	switch filepath.Base(path) {
+	case "project.yaml":
+		entity = &Project{}
+	case "phase.yaml":
+		entity = &Phase{}
+	default:
+		if filepath.Base(filepath.Dir(path)) != "tasks" {
+			return fields
+		}
+		entity = &Task{}
+	}
+
+	if err := yaml.Unmarshal(data, entity); err != nil {
+		return fields
+	}
+	return entity
+}
+
+// lockForPath takes the project lock for a file inside the projects tree
+func lockForPath(path string) (func(), error) {
+	rel, err := filepath.Rel(filepath.Join(projectsPath, "projects"), path)
+	if err != nil {
+		return nil, err
+	}
+	projectID := strings.Split(filepath.ToSlash(rel), "/")[0]
+	return store.Lock(projectID)
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for key := range a {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for key := range b {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFieldValue(value interface{}, present bool) string {
+	if !present {
+		return "(missing)"
+	}
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []interface{}, map[string]interface{}:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return "[" + truncate(strings.Join(strings.Fields(string(data)), " "), 60) + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func init() {
+	syncConflictsCmd.Flags().StringP("project", "p", "", "Only scan a specific project")
+	syncConflictsCmd.Flags().String("resolve", "", "Resolve conflicts (take-mine, take-theirs, merge)")
+
+	syncCmd.AddCommand(syncConflictsCmd)
+}
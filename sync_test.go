@@ -0,0 +1,277 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func decodeYAMLMap(t *testing.T, text string) map[string]interface{} {
+	t.Helper()
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(text), &fields); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+	return fields
+}
+
+const mergeBaseHistory = `
+history:
+  - {timestamp: "2026-10-01T09:00:00Z", author: ann, field: status, from: todo, to: in_progress}
+`
+
+func TestMergeConflictFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		mine   string
+		theirs string
+		want   string
+	}{
+		{
+			name: "field changed on one side wins even when the other is newer",
+			mine: `
+updated: "2026-10-02T10:00:00Z"
+priority: high
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: priority, from: low, to: high}
+`,
+			theirs: `
+updated: "2026-10-03T10:00:00Z"
+priority: low
+title: Renamed
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: title, from: Old, to: Renamed}
+`,
+			want: `
+updated: "2026-10-03T10:00:00Z"
+priority: high
+title: Renamed
+revision: 1
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: priority, from: low, to: high}
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: title, from: Old, to: Renamed}
+`,
+		},
+		{
+			name: "field changed on both sides takes the newest",
+			mine: `
+updated: "2026-10-02T10:00:00Z"
+status: review
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: status, from: in_progress, to: review}
+`,
+			theirs: `
+updated: "2026-10-03T10:00:00Z"
+status: blocked
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: status, from: in_progress, to: blocked}
+`,
+			want: `
+updated: "2026-10-03T10:00:00Z"
+status: blocked
+revision: 1
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: status, from: in_progress, to: review}
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: status, from: in_progress, to: blocked}
+`,
+		},
+		{
+			name: "removed dependency stays removed",
+			mine: `
+dependency_ids: [T1.1]
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: dependency_ids, from: "T1.1, T1.2", to: T1.1}
+`,
+			theirs: `
+dependency_ids: [T1.1, T1.2]
+` + mergeBaseHistory,
+			want: `
+dependency_ids: [T1.1]
+revision: 1
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: dependency_ids, from: "T1.1, T1.2", to: T1.1}
+`,
+		},
+		{
+			name: "dependencies changed on both sides merge against the ancestor",
+			mine: `
+dependency_ids: [T1.2, T1.3]
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: dependency_ids, from: "T1.1, T1.2", to: T1.2}
+  - {timestamp: "2026-10-02T11:00:00Z", author: ann, field: dependency_ids, from: T1.2, to: "T1.2, T1.3"}
+`,
+			theirs: `
+dependency_ids: [T1.1, T1.4]
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: dependency_ids, from: "T1.1, T1.2", to: T1.1}
+  - {timestamp: "2026-10-03T11:00:00Z", author: bob, field: dependency_ids, from: T1.1, to: "T1.1, T1.4"}
+`,
+			want: `
+dependency_ids: [T1.3, T1.4]
+revision: 1
+` + mergeBaseHistory + `
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: dependency_ids, from: "T1.1, T1.2", to: T1.2}
+  - {timestamp: "2026-10-02T11:00:00Z", author: ann, field: dependency_ids, from: T1.2, to: "T1.2, T1.3"}
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: dependency_ids, from: "T1.1, T1.2", to: T1.1}
+  - {timestamp: "2026-10-03T11:00:00Z", author: bob, field: dependency_ids, from: T1.1, to: "T1.1, T1.4"}
+`,
+		},
+		{
+			name: "components are merged by id",
+			mine: `
+updated: "2026-10-02T10:00:00Z"
+components:
+  - {id: C1, title: Login, status: done}
+  - {id: C2, title: Logout, status: todo}
+history:
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: components.C1.status, from: todo, to: done}
+`,
+			theirs: `
+updated: "2026-10-03T10:00:00Z"
+components:
+  - {id: C1, title: Sign in, status: todo}
+  - {id: C3, title: Reset, status: todo}
+history:
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: components.C1.title, from: Login, to: Sign in}
+`,
+			want: `
+updated: "2026-10-03T10:00:00Z"
+components:
+  - {id: C1, title: Sign in, status: done}
+  - {id: C2, title: Logout, status: todo}
+  - {id: C3, title: Reset, status: todo}
+revision: 1
+history:
+  - {timestamp: "2026-10-02T10:00:00Z", author: ann, field: components.C1.status, from: todo, to: done}
+  - {timestamp: "2026-10-03T10:00:00Z", author: bob, field: components.C1.title, from: Login, to: Sign in}
+`,
+		},
+		{
+			name: "time logs of both sides are kept",
+			mine: `
+updated: "2026-10-02T10:00:00Z"
+time_tracking:
+  estimated_hours: 8
+  time_logs:
+    - {date: "2026-10-01", hours: 2, author: ann}
+    - {date: "2026-10-02", hours: 1, author: ann}
+`,
+			theirs: `
+updated: "2026-10-03T10:00:00Z"
+time_tracking:
+  estimated_hours: 8
+  time_logs:
+    - {date: "2026-10-01", hours: 2, author: ann}
+    - {date: "2026-10-03", hours: 3, author: bob}
+`,
+			want: `
+updated: "2026-10-03T10:00:00Z"
+time_tracking:
+  estimated_hours: 8
+  time_logs:
+    - {date: "2026-10-01", hours: 2, author: ann}
+    - {date: "2026-10-02", hours: 1, author: ann}
+    - {date: "2026-10-03", hours: 3, author: bob}
+revision: 1
+`,
+		},
+		{
+			name:   "revision is bumped past both sides",
+			mine:   `revision: 7`,
+			theirs: `revision: 5`,
+			want:   `revision: 8`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mergeConflictFields(decodeYAMLMap(t, test.mine), decodeYAMLMap(t, test.theirs))
+
+			// Normalise through YAML so typed values such as []HistoryEntry compare equal
+			data, err := yaml.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := decodeYAMLMap(t, test.want); !reflect.DeepEqual(decodeYAMLMap(t, string(data)), want) {
+				wantData, _ := yaml.Marshal(want)
+				t.Errorf("merged:\n%s\nwant:\n%s", data, wantData)
+			}
+		})
+	}
+}
+
+func TestMergeConflictFieldsRollsUpTimeLogs(t *testing.T) {
+	mine := decodeYAMLMap(t, `
+id: T1.1
+time_tracking:
+  actual_hours: 3
+  time_logs:
+    - {date: "2026-10-01", hours: 3, author: ann}
+`)
+	theirs := decodeYAMLMap(t, `
+id: T1.1
+time_tracking:
+  actual_hours: 2
+  time_logs:
+    - {date: "2026-10-02", hours: 2, author: bob}
+`)
+	task, ok := typedEntity("/p/phases/P1/tasks/T1.1.yaml", mergeConflictFields(mine, theirs)).(*Task)
+	if !ok {
+		t.Fatal("merged task did not decode as a Task")
+	}
+	rollupActualHours(task)
+	if len(task.TimeTracking.TimeLogs) != 2 || task.TimeTracking.ActualHours != 5 {
+		t.Errorf("got %d logs and %vh, want 2 logs and 5h", len(task.TimeTracking.TimeLogs), task.TimeTracking.ActualHours)
+	}
+}
+
+// captureStderr returns what fn writes to standard error
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = w
+	fn()
+	os.Stderr = saved
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestWarnAboutConflictsChecksOnlyTheProject(t *testing.T) {
+	saved := projectsPath
+	projectsPath = t.TempDir()
+	t.Cleanup(func() { projectsPath = saved })
+
+	for _, path := range []string{
+		"projects/web/tasks/T1.1 (Ann's conflicted copy 2026-10-01).yaml",
+		"projects/api/phases/P1/tasks/T1.1 (Bob's conflicted copy 2026-10-01).yaml",
+		"projects/api/phases/P1/tasks/T1.2 (Bob's conflicted copy 2026-10-02).yaml",
+	} {
+		path = filepath.Join(projectsPath, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("id: T1.1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out := captureStderr(t, func() { warnAboutConflicts("api") }); !strings.Contains(out, "2 Dropbox conflicted copies found in project 'api'") {
+		t.Errorf("warning for api: %q", out)
+	}
+	if out := captureStderr(t, func() { warnAboutConflicts("docs") }); out != "" {
+		t.Errorf("warning for a project without conflicts: %q", out)
+	}
+	if out := captureStderr(t, func() { warnAboutConflicts("") }); out != "" {
+		t.Errorf("commands spanning all projects walked the tree: %q", out)
+	}
+}
@@ -3,93 +3,102 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 type Task struct {
-	ID          string `yaml:"id"`
-	Title       string `yaml:"title"`
-	ProjectID   string `yaml:"project_id"`
-	PhaseID     string `yaml:"phase_id,omitempty"`
-	Status      string `yaml:"status"`
-	Priority    string `yaml:"priority"`
-	Assignee    string `yaml:"assignee,omitempty"`
-	Reporter    string `yaml:"reporter,omitempty"`
-	Created     string `yaml:"created"`
-	Updated     string `yaml:"updated"`
-	DueDate     string `yaml:"due_date,omitempty"`
-	StoryPoints int    `yaml:"story_points,omitempty"`
-	Description string `yaml:"description"`
+	ID          string `yaml:"id" json:"id"`
+	Title       string `yaml:"title" json:"title"`
+	ProjectID   string `yaml:"project_id" json:"project_id"`
+	PhaseID     string `yaml:"phase_id,omitempty" json:"phase_id,omitempty"`
+	Status      string `yaml:"status" json:"status"`
+	Priority    string `yaml:"priority" json:"priority"`
+	Assignee    string `yaml:"assignee,omitempty" json:"assignee,omitempty"`
+	Reporter    string `yaml:"reporter,omitempty" json:"reporter,omitempty"`
+	Created     string `yaml:"created" json:"created"`
+	Updated     string `yaml:"updated" json:"updated"`
+	Revision    int    `yaml:"revision,omitempty" json:"revision,omitempty"`
+	DueDate     string `yaml:"due_date,omitempty" json:"due_date,omitempty"`
+	StoryPoints int    `yaml:"story_points,omitempty" json:"story_points,omitempty"`
+	Description string `yaml:"description" json:"description"`
+
+	// Former IDs of the task, kept so lookups still resolve after a move
+	Aliases []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+
+	// The collaboration marker the task was created from by 'dppm collab sync'
+	Collab *CollabLink `yaml:"collab,omitempty" json:"collab,omitempty"`
 
 	// Advanced features
-	Components    []Component  `yaml:"components,omitempty"`
-	Issues        []Issue      `yaml:"issues,omitempty"`
-	DependencyIDs []string     `yaml:"dependency_ids,omitempty"`
-	BlockedBy     []string     `yaml:"blocked_by,omitempty"`
-	Blocking      []string     `yaml:"blocking,omitempty"`
-	Labels        []string     `yaml:"labels,omitempty"`
-	Attachments   []string     `yaml:"attachments,omitempty"`
-	Comments      []Comment    `yaml:"comments,omitempty"`
-	TimeTracking  TimeTracking `yaml:"time_tracking,omitempty"`
-	Progress      Progress     `yaml:"progress,omitempty"`
+	Components    []Component  `yaml:"components,omitempty" json:"components,omitempty"`
+	Issues        []Issue      `yaml:"issues,omitempty" json:"issues,omitempty"`
+	DependencyIDs []string     `yaml:"dependency_ids,omitempty" json:"dependency_ids,omitempty"`
+	BlockedBy     []string     `yaml:"blocked_by,omitempty" json:"blocked_by,omitempty"`
+	Blocking      []string     `yaml:"blocking,omitempty" json:"blocking,omitempty"`
+	Labels        []string     `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Attachments   []string     `yaml:"attachments,omitempty" json:"attachments,omitempty"`
+	Comments      []Comment    `yaml:"comments,omitempty" json:"comments,omitempty"`
+	TimeTracking  TimeTracking `yaml:"time_tracking,omitempty" json:"time_tracking,omitempty"`
+	Progress      Progress     `yaml:"progress,omitempty" json:"progress,omitempty"`
+
+	// Append-only change log
+	History []HistoryEntry `yaml:"history,omitempty" json:"history,omitempty"`
 }
 
 type Component struct {
-	ID          string `yaml:"id"`
-	Title       string `yaml:"title"`
-	Status      string `yaml:"status"`
-	Type        string `yaml:"type"`
-	Description string `yaml:"description,omitempty"`
-	AssignedTo  string `yaml:"assigned_to,omitempty"`
-	Created     string `yaml:"created"`
-	Updated     string `yaml:"updated"`
+	ID          string `yaml:"id" json:"id"`
+	Title       string `yaml:"title" json:"title"`
+	Status      string `yaml:"status" json:"status"`
+	Type        string `yaml:"type" json:"type"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	AssignedTo  string `yaml:"assigned_to,omitempty" json:"assigned_to,omitempty"`
+	Created     string `yaml:"created" json:"created"`
+	Updated     string `yaml:"updated" json:"updated"`
 }
 
 type Issue struct {
-	ID              string `yaml:"id"`
-	Title           string `yaml:"title"`
-	Type            string `yaml:"type"`
-	Status          string `yaml:"status"`
-	Severity        string `yaml:"severity,omitempty"`
-	ParentComponent string `yaml:"parent_component,omitempty"`
-	Description     string `yaml:"description"`
-	ReportedBy      string `yaml:"reported_by,omitempty"`
-	AssignedTo      string `yaml:"assigned_to,omitempty"`
-	Created         string `yaml:"created"`
-	Updated         string `yaml:"updated"`
+	ID              string `yaml:"id" json:"id"`
+	Title           string `yaml:"title" json:"title"`
+	Type            string `yaml:"type" json:"type"`
+	Status          string `yaml:"status" json:"status"`
+	Severity        string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	ParentComponent string `yaml:"parent_component,omitempty" json:"parent_component,omitempty"`
+	Description     string `yaml:"description" json:"description"`
+	ReportedBy      string `yaml:"reported_by,omitempty" json:"reported_by,omitempty"`
+	AssignedTo      string `yaml:"assigned_to,omitempty" json:"assigned_to,omitempty"`
+	Created         string `yaml:"created" json:"created"`
+	Updated         string `yaml:"updated" json:"updated"`
 }
 
 type Comment struct {
-	Timestamp string `yaml:"timestamp"`
-	Author    string `yaml:"author"`
-	Content   string `yaml:"content"`
-	Type      string `yaml:"type"`
+	Timestamp string `yaml:"timestamp" json:"timestamp"`
+	Author    string `yaml:"author" json:"author"`
+	Content   string `yaml:"content" json:"content"`
+	Type      string `yaml:"type" json:"type"`
 }
 
 type TimeTracking struct {
-	EstimatedHours int       `yaml:"estimated_hours,omitempty"`
-	ActualHours    int       `yaml:"actual_hours,omitempty"`
-	TimeLogs       []TimeLog `yaml:"time_logs,omitempty"`
+	EstimatedHours int       `yaml:"estimated_hours,omitempty" json:"estimated_hours,omitempty"`
+	ActualHours    float64   `yaml:"actual_hours,omitempty" json:"actual_hours,omitempty"`
+	TimeLogs       []TimeLog `yaml:"time_logs,omitempty" json:"time_logs,omitempty"`
 }
 
 type TimeLog struct {
-	Date        string  `yaml:"date"`
-	Hours       float32 `yaml:"hours"`
-	Description string  `yaml:"description"`
-	Author      string  `yaml:"author"`
+	Date        string  `yaml:"date" json:"date"`
+	Hours       float32 `yaml:"hours" json:"hours"`
+	Description string  `yaml:"description" json:"description"`
+	Author      string  `yaml:"author" json:"author"`
 }
 
 type Progress struct {
-	TotalComponents      int `yaml:"total_components"`
-	CompletedComponents  int `yaml:"completed_components"`
-	CompletionPercentage int `yaml:"completion_percentage"`
-	TotalIssues          int `yaml:"total_issues"`
-	ResolvedIssues       int `yaml:"resolved_issues"`
-	OpenBugs             int `yaml:"open_bugs"`
+	TotalComponents      int `yaml:"total_components" json:"total_components"`
+	CompletedComponents  int `yaml:"completed_components" json:"completed_components"`
+	CompletionPercentage int `yaml:"completion_percentage" json:"completion_percentage"`
+	TotalIssues          int `yaml:"total_issues" json:"total_issues"`
+	ResolvedIssues       int `yaml:"resolved_issues" json:"resolved_issues"`
+	OpenBugs             int `yaml:"open_bugs" json:"open_bugs"`
 }
 
 var taskCmd = &cobra.Command{
@@ -104,6 +113,13 @@ Available Commands:
   create       Create a new task
   show         Display detailed task information
   update       Update task properties
+  history      Show the change history of a task
+  move         Move a task to another phase
+  next-id      Print the next free task ID of a phase
+  log          Log hours worked on a task
+  start        Start a timer on a task
+  stop         Stop the running timer and log the time
+  comment      Comment threads on tasks
   list         List tasks (use 'dppm list tasks' instead)
   component    Manage task components
   issue        Manage task issues
@@ -128,12 +144,15 @@ Tasks are the primary work units that can be broken into components and
 tracked with issues and dependencies.
 
 Arguments:
-  task-id    Unique identifier for the task (required)
+  task-id    Unique identifier for the task (optional)
              Accepts any valid identifier with letters, numbers, hyphens, underscores, and dots
              Examples: auth-system, file-browser, T1.1, bug-login, implement_feature
              Note: IDs starting with 'bug-' are treated as bug reports
+             When omitted, the next free ID of the phase is used
+             (see 'dppm task next-id')
 
 Examples:
+  dppm task create --project web-app --phase P1 --title "Login form"   # Gets e.g. T1.4
   dppm task create auth-system --project dash-lxd --title "User Authentication System" --description "Implement JWT-based authentication with login/logout functionality"
   dppm task create file-ops --project web-app --title "File Operations" --phase phase-1 --description "Create file upload, download, and management features"
   dppm task create bug-fix --project api --title "Fix Login Bug" --priority high --description "Resolve authentication timeout issues reported by users"
@@ -141,18 +160,23 @@ Examples:
 💡 AI Best Practice:
   Always include descriptions for better task context and collaboration.
   Descriptions help other AI agents understand task requirements and scope.`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		taskID := args[0]
+		var taskID string
+		if len(args) > 0 {
+			taskID = args[0]
+		}
 
 		title, _ := cmd.Flags().GetString("title")
 		projectID, _ := cmd.Flags().GetString("project")
 		phaseID, _ := cmd.Flags().GetString("phase")
 
 		// Validate task ID for security (with phase context if available)
-		if err := ValidateTaskID(taskID, phaseID); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+		if taskID != "" {
+			if err := ValidateTaskID(taskID, phaseID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
 		}
 		description, _ := cmd.Flags().GetString("description")
 		priority, _ := cmd.Flags().GetString("priority")
@@ -183,21 +207,60 @@ Examples:
 		}
 
 		// Check if project exists
-		projectPath := filepath.Join(projectsPath, "projects", projectID)
-		if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		exists, err := store.ProjectExists(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking project existence: %v\n", err)
+			os.Exit(1)
+		}
+		if !exists {
 			fmt.Fprintf(os.Stderr, "Error: Project '%s' does not exist\n", projectID)
 			fmt.Fprintf(os.Stderr, "Create it first with: dppm project create %s\n", projectID)
 			os.Exit(1)
 		}
 
 		// Check if phase exists within the project
-		phasePath := filepath.Join(projectsPath, "projects", projectID, "phases", phaseID)
-		if _, err := os.Stat(phasePath); os.IsNotExist(err) {
+		exists, err = store.PhaseExists(projectID, phaseID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking phase existence: %v\n", err)
+			os.Exit(1)
+		}
+		if !exists {
 			fmt.Fprintf(os.Stderr, "Error: Phase '%s' does not exist in project '%s'\n", phaseID, projectID)
 			fmt.Fprintf(os.Stderr, "Create it first with: dppm phase create %s --project %s\n", phaseID, projectID)
 			os.Exit(1)
 		}
 
+		unlock, err := store.Lock(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		// Pick the next free ID of the phase when none was given
+		if taskID == "" {
+			tasks, err := store.ListTasks(projectID)
+			if err == nil {
+				taskID, err = nextTaskID(tasks, phaseID, "")
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Cannot pick a task ID: %v\n", err)
+				unlock()
+				os.Exit(1)
+			}
+		}
+
+		// Refuse to overwrite an existing task
+		if existing, err := store.LoadTask(projectID, taskID); err == nil {
+			if existing.ID != taskID {
+				fmt.Fprintf(os.Stderr, "Error: Task ID '%s' was used by task '%s' before it was moved\n", taskID, existing.ID)
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: Task '%s' already exists in project '%s'\n", taskID, projectID)
+			}
+			unlock()
+			os.Exit(1)
+		}
+
 		if title == "" {
 			title = taskID
 		}
@@ -221,9 +284,9 @@ Examples:
 			Status:        "todo",
 			Priority:      priority,
 			Assignee:      assignee,
-			Reporter:      "dppm-user",
-			Created:       time.Now().Format("2006-01-02"),
-			Updated:       time.Now().Format("2006-01-02"),
+			Reporter:      currentAuthor(),
+			Created:       nowTimestamp(),
+			Updated:       nowTimestamp(),
 			Description:   description,
 			Components:    []Component{},
 			Issues:        []Issue{},
@@ -234,27 +297,7 @@ Examples:
 			Comments:      []Comment{},
 		}
 
-		// Create task directory structure
-		var taskDir string
-		if phaseID != "" {
-			taskDir = filepath.Join(projectsPath, "projects", projectID, "phases", phaseID, "tasks")
-		} else {
-			taskDir = filepath.Join(projectsPath, "projects", projectID, "tasks")
-		}
-
-		if err := os.MkdirAll(taskDir, 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating task directory: %v\n", err)
-			return
-		}
-
-		taskFile := filepath.Join(taskDir, taskID+".yaml")
-		data, err := yaml.Marshal(task)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling task: %v\n", err)
-			return
-		}
-
-		if err := os.WriteFile(taskFile, data, 0644); err != nil {
+		if err := store.SaveTask(&task); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing task file: %v\n", err)
 			return
 		}
@@ -269,7 +312,7 @@ Examples:
 		fmt.Printf("  dppm task update %s --status in_progress  # Start working on task\n", taskID)
 
 		// Show other ready tasks
-		if guidance, err := getAIGuidance(); err == nil {
+		if guidance, err := getAIGuidance(projectID); err == nil {
 			readyCount := 0
 			for _, guide := range guidance {
 				if guide["type"] == "READY" && guide["task_id"] != taskID {
@@ -283,10 +326,10 @@ Examples:
 		}
 
 		// Suggest next task creation
-		if suggestions, err := getSuggestedTaskIDs(); err == nil {
+		if suggestions, err := getSuggestedTaskIDs(projectID); err == nil {
 			for _, suggestion := range suggestions {
-				if suggestion != taskID {
-					fmt.Printf("  dppm task create %s --title \"Next Task\"  # Create follow-up task\n", suggestion)
+				if suggestion != taskID && ValidateTaskID(suggestion, phaseID) == nil {
+					fmt.Printf("  dppm task create %s --project %s --phase %s --title \"Next Task\"  # Create follow-up task\n", suggestion, projectID, phaseID)
 					break
 				}
 			}
@@ -307,7 +350,8 @@ Arguments:
 
 Examples:
   dppm task show auth-system
-  dppm task show file-ops --project web-app`,
+  dppm task show file-ops --project web-app
+  dppm task show T1.1 --output json`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		taskID := args[0]
@@ -319,7 +363,7 @@ Examples:
 			return
 		}
 
-		showTask(projectID, "", taskID)
+		showTask(projectID, taskID)
 	},
 }
 
@@ -334,100 +378,85 @@ will be changed; others remain unchanged.
 Arguments:
   task-id    Task identifier to update
 
+Status Workflow:
+  Status changes must follow the project's workflow (see 'dppm project
+  workflow'). By default a todo task cannot jump straight to done, and done
+  is final. --force overrides this; the change is marked as forced in the
+  task history.
+
+Concurrency:
+  Every save increments the task's revision (see 'dppm task show').
+  Pass --if-revision N to refuse the update when someone else has changed
+  the task since you read revision N.
+
+Collaboration Markers:
+  Setting a task linked by 'dppm collab sync' to done appends a
+  ::DONE:ID:: marker to its markdown file. Run the update from the folder
+  sync ran in; otherwise the next sync adds the marker.
+
 Examples:
   dppm task update auth-system --status in_progress
   dppm task update file-ops --assignee john-doe --priority high
-  dppm task update bug-fix --status done --description "Fixed login issue"`,
+  dppm task update bug-fix --status done --description "Fixed login issue"
+  dppm task update T1.1 --status review --if-revision 3
+  dppm task update T1.1 --status todo --force`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		taskID := args[0]
 		projectID, _ := cmd.Flags().GetString("project")
 
 		if projectID == "" {
-			searchAndUpdateTask(taskID, cmd)
+			if !searchAndUpdateTask(taskID, cmd) {
+				os.Exit(1)
+			}
 			return
 		}
 
-		updateTask(projectID, "", taskID, cmd)
+		if !updateTask(projectID, taskID, cmd) {
+			os.Exit(1)
+		}
+		fmt.Printf("Task '%s' updated successfully\n", taskID)
 	},
 }
 
-func searchAndShowTask(taskID string) {
-	projectsDir := filepath.Join(projectsPath, "projects")
-
-	err := filepath.Walk(projectsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.Name() == taskID+".yaml" {
-			// Read and display task
-			data, err := os.ReadFile(path)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading task file: %v\n", err)
-				return nil
-			}
-
-			var task Task
-			if err := yaml.Unmarshal(data, &task); err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing task file: %v\n", err)
-				return nil
-			}
-
-			displayTask(task)
-			return filepath.SkipDir
-		}
-		return nil
-	})
-
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
+// loadTaskForCommand loads a task from the given project, or searches all
+// projects when projectID is empty
+func loadTaskForCommand(projectID, taskID string) (*Task, error) {
+	if projectID == "" {
+		return findTask(store, taskID)
 	}
+	return store.LoadTask(projectID, taskID)
 }
 
-func showTask(projectID, phaseID, taskID string) {
-	var taskFile string
-	if phaseID != "" {
-		taskFile = filepath.Join(projectsPath, "projects", projectID, "phases", phaseID, "tasks", taskID+".yaml")
-	} else {
-		// Try both locations
-		taskFile = filepath.Join(projectsPath, "projects", projectID, "tasks", taskID+".yaml")
-		if _, err := os.Stat(taskFile); os.IsNotExist(err) {
-			// Search in phases
-			phasesDir := filepath.Join(projectsPath, "projects", projectID, "phases")
-			err := filepath.Walk(phasesDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return nil
-				}
-				if info.Name() == taskID+".yaml" {
-					taskFile = path
-					return filepath.SkipDir
-				}
-				return nil
-			})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Task '%s' not found in project '%s'\n", taskID, projectID)
-				return
-			}
-		}
-	}
-
-	data, err := os.ReadFile(taskFile)
+func searchAndShowTask(taskID string) {
+	task, err := findTask(store, taskID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading task file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
 		return
 	}
 
-	var task Task
-	if err := yaml.Unmarshal(data, &task); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing task file: %v\n", err)
+	displayTask(*task)
+}
+
+func showTask(projectID, taskID string) {
+	task, err := store.LoadTask(projectID, taskID)
+	if err != nil {
+		if isNotFound(err) {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found in project '%s'\n", taskID, projectID)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error reading task file: %v\n", err)
+		}
 		return
 	}
 
-	displayTask(task)
+	displayTask(*task)
 }
 
 func displayTask(task Task) {
+	if printStructured(task) {
+		return
+	}
+
 	fmt.Printf("Task: %s\n", task.ID)
 	fmt.Printf("================\n\n")
 	fmt.Printf("Title: %s\n", task.Title)
@@ -435,6 +464,12 @@ func displayTask(task Task) {
 	if task.PhaseID != "" {
 		fmt.Printf("Phase: %s\n", task.PhaseID)
 	}
+	if len(task.Aliases) > 0 {
+		fmt.Printf("Formerly: %s\n", strings.Join(task.Aliases, ", "))
+	}
+	if task.Collab != nil {
+		fmt.Printf("Collab: ::%s:%s:: in %s\n", task.Collab.Agent, task.Collab.ID, task.Collab.File)
+	}
 	fmt.Printf("Status: %s\n", task.Status)
 	fmt.Printf("Priority: %s\n", task.Priority)
 
@@ -447,6 +482,7 @@ func displayTask(task Task) {
 
 	fmt.Printf("Created: %s\n", task.Created)
 	fmt.Printf("Updated: %s\n", task.Updated)
+	fmt.Printf("Revision: %d\n", task.Revision)
 
 	if task.DueDate != "" {
 		fmt.Printf("Due Date: %s\n", task.DueDate)
@@ -455,6 +491,10 @@ func displayTask(task Task) {
 		fmt.Printf("Story Points: %d\n", task.StoryPoints)
 	}
 
+	if task.TimeTracking.EstimatedHours > 0 || len(task.TimeTracking.TimeLogs) > 0 {
+		printTimeTracking(task.TimeTracking)
+	}
+
 	if task.Description != "" {
 		fmt.Printf("\nDescription:\n%s\n", task.Description)
 	}
@@ -478,6 +518,7 @@ func displayTask(task Task) {
 		for _, comp := range task.Components {
 			fmt.Printf("  - %s (%s): %s\n", comp.ID, comp.Status, comp.Title)
 		}
+		printProgress(task.Progress)
 	}
 
 	if len(task.Issues) > 0 {
@@ -485,131 +526,155 @@ func displayTask(task Task) {
 		for _, issue := range task.Issues {
 			fmt.Printf("  - %s (%s): %s\n", issue.ID, issue.Status, issue.Title)
 		}
+		printIssueProgress(task.Progress)
 	}
+
+	printLatestComments(task.Comments)
 }
 
-func searchAndUpdateTask(taskID string, cmd *cobra.Command) {
-	projectsDir := filepath.Join(projectsPath, "projects")
-	updated := false
+func searchAndUpdateTask(taskID string, cmd *cobra.Command) bool {
+	task, err := findTask(store, taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
+		return false
+	}
 
-	err := filepath.Walk(projectsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
+	if !updateTask(task.ProjectID, taskID, cmd) {
+		return false
+	}
+	fmt.Printf("Task '%s' updated successfully in project '%s'\n", taskID, task.ProjectID)
+	return true
+}
 
-		if info.Name() == taskID+".yaml" {
-			// Extract project ID from path
-			rel, _ := filepath.Rel(projectsDir, path)
-			parts := filepath.SplitList(rel)
-			if len(parts) > 0 {
-				projectID := parts[0]
-
-				// Update task
-				if updateTaskFile(path, cmd) {
-					fmt.Printf("Task '%s' updated successfully in project '%s'\n", taskID, projectID)
-					updated = true
-				}
-				return filepath.SkipDir
-			}
+// updateTask re-reads the task under the project lock and applies the update
+func updateTask(projectID, taskID string, cmd *cobra.Command) bool {
+	unlock, err := store.Lock(projectID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return false
+	}
+	defer unlock()
+
+	task, err := store.LoadTask(projectID, taskID)
+	if err != nil {
+		if isNotFound(err) {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found in project '%s'\n", taskID, projectID)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error reading task file: %v\n", err)
 		}
-		return nil
-	})
+		return false
+	}
 
-	if err != nil || !updated {
-		fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
+	if err := checkIfRevision(cmd, "task", taskID, task.Revision); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return false
 	}
+
+	return updateTaskFile(task, cmd)
 }
 
-func updateTask(projectID, phaseID, taskID string, cmd *cobra.Command) {
-	var taskFile string
-	if phaseID != "" {
-		taskFile = filepath.Join(projectsPath, "projects", projectID, "phases", phaseID, "tasks", taskID+".yaml")
-	} else {
-		// Try both locations
-		taskFile = filepath.Join(projectsPath, "projects", projectID, "tasks", taskID+".yaml")
-		if _, err := os.Stat(taskFile); os.IsNotExist(err) {
-			// Search in phases
-			phasesDir := filepath.Join(projectsPath, "projects", projectID, "phases")
-			err := filepath.Walk(phasesDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return nil
-				}
-				if info.Name() == taskID+".yaml" {
-					taskFile = path
-					return filepath.SkipDir
-				}
-				return nil
-			})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Task '%s' not found in project '%s'\n", taskID, projectID)
-				return
-			}
+// modifyTask re-reads a task under its project lock, applies change and saves
+// the result. An empty projectID searches all projects for the task. The
+// --if-revision flag of cmd, if it has one, is checked against the re-read
+// task; cmd may be nil.
+func modifyTask(cmd *cobra.Command, projectID, taskID string, change func(task *Task) error) (*Task, error) {
+	task, err := loadTaskForCommand(projectID, taskID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("task '%s' not found", taskID)
 		}
+		return nil, err
 	}
+	projectID = task.ProjectID
 
-	if updateTaskFile(taskFile, cmd) {
-		fmt.Printf("Task '%s' updated successfully\n", taskID)
+	unlock, err := store.Lock(projectID)
+	if err != nil {
+		return nil, err
 	}
-}
+	defer unlock()
 
-func updateTaskFile(taskFile string, cmd *cobra.Command) bool {
-	data, err := os.ReadFile(taskFile)
+	task, err = store.LoadTask(projectID, taskID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading task file: %v\n", err)
-		return false
+		return nil, err
+	}
+	if cmd != nil {
+		if err := checkIfRevision(cmd, "task", task.ID, task.Revision); err != nil {
+			return nil, err
+		}
 	}
 
-	var task Task
-	if err := yaml.Unmarshal(data, &task); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing task file: %v\n", err)
-		return false
+	if err := change(task); err != nil {
+		return nil, err
 	}
+	task.Updated = nowTimestamp()
+
+	if err := store.SaveTask(task); err != nil {
+		return nil, fmt.Errorf("failed to save task '%s': %v", taskID, err)
+	}
+	return task, nil
+}
+
+// updateTaskFile applies the update flags to a task and saves it through the store
+func updateTaskFile(task *Task, cmd *cobra.Command) bool {
+	wasDone := task.Status == "done"
 
-	// Update fields if provided
+	// Update fields if provided, recording each change in the task history
 	if cmd.Flags().Changed("status") {
 		status, _ := cmd.Flags().GetString("status")
-		task.Status = status
+		force, _ := cmd.Flags().GetBool("force")
+		if err := changeTaskStatus(task, status, force); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
 	}
 	if cmd.Flags().Changed("priority") {
 		priority, _ := cmd.Flags().GetString("priority")
+		recordChange(task, "priority", task.Priority, priority)
 		task.Priority = priority
 	}
 	if cmd.Flags().Changed("assignee") {
 		assignee, _ := cmd.Flags().GetString("assignee")
+		recordChange(task, "assignee", task.Assignee, assignee)
 		task.Assignee = assignee
 	}
 	if cmd.Flags().Changed("title") {
 		title, _ := cmd.Flags().GetString("title")
+		recordChange(task, "title", task.Title, title)
 		task.Title = title
 	}
 	if cmd.Flags().Changed("description") {
 		description, _ := cmd.Flags().GetString("description")
+		recordChange(task, "description", task.Description, description)
 		task.Description = description
 	}
 	if cmd.Flags().Changed("due-date") {
 		dueDate, _ := cmd.Flags().GetString("due-date")
+		recordChange(task, "due_date", task.DueDate, dueDate)
 		task.DueDate = dueDate
 	}
+	if cmd.Flags().Changed("estimated-hours") {
+		estimatedHours, _ := cmd.Flags().GetInt("estimated-hours")
+		recordChange(task, "time_tracking.estimated_hours", strconv.Itoa(task.TimeTracking.EstimatedHours), strconv.Itoa(estimatedHours))
+		task.TimeTracking.EstimatedHours = estimatedHours
+	}
 	if cmd.Flags().Changed("story-points") {
 		storyPoints, _ := cmd.Flags().GetInt("story-points")
+		recordChange(task, "story_points", strconv.Itoa(task.StoryPoints), strconv.Itoa(storyPoints))
 		task.StoryPoints = storyPoints
 	}
 
 	// Update timestamp
-	task.Updated = time.Now().Format("2006-01-02")
-
-	// Write back to file
-	data, err = yaml.Marshal(task)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling task: %v\n", err)
-		return false
-	}
+	task.Updated = nowTimestamp()
 
-	if err := os.WriteFile(taskFile, data, 0644); err != nil {
+	// Write back through the store
+	if err := store.SaveTask(task); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing task file: %v\n", err)
 		return false
 	}
 
+	if !wasDone {
+		markCollabDone(task)
+	}
 	return true
 }
 
@@ -636,6 +701,9 @@ func init() {
 	updateTaskCmd.Flags().StringP("description", "d", "", "Task description")
 	updateTaskCmd.Flags().String("due-date", "", "Due date (YYYY-MM-DD)")
 	updateTaskCmd.Flags().Int("story-points", 0, "Story points")
+	updateTaskCmd.Flags().Int("estimated-hours", 0, "Estimated hours of work")
+	updateTaskCmd.Flags().Int("if-revision", 0, "Only update if the task is still at this revision")
+	updateTaskCmd.Flags().Bool("force", false, "Allow a status change the project workflow forbids (recorded in history)")
 
 	taskCmd.AddCommand(createTaskCmd)
 	taskCmd.AddCommand(showTaskCmd)
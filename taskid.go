@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// taskIDPartsRegex splits a task ID into phase number, task number, subtask or
+// bug part and suffix, following the formats accepted by ValidateTaskID
+var taskIDPartsRegex = regexp.MustCompile(`^T([1-9][0-9]*)\.([1-9][0-9]*)(\.[1-9][0-9]*|\.B[1-9][0-9]*)?(-[a-zA-Z0-9][a-zA-Z0-9-_]*)?$`)
+
+// NextTaskID is the structured output of 'dppm task next-id'
+type NextTaskID struct {
+	ProjectID string `yaml:"project_id" json:"project_id"`
+	PhaseID   string `yaml:"phase_id" json:"phase_id"`
+	NextID    string `yaml:"next_id" json:"next_id"`
+}
+
+var taskNextIDCmd = &cobra.Command{
+	Use:   "next-id",
+	Short: "Print the next free task ID of a phase",
+	Long: `Next Free Task ID
+
+Prints the next free task ID of a phase: one past the highest T<phase>.N
+used by any task, including former IDs of moved tasks. This is the ID
+'dppm task create' picks when no task ID is given.
+
+Examples:
+  dppm task next-id --project web-app --phase P2
+  dppm task create $(dppm task next-id --project web-app --phase P2) --project web-app --phase P2`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID, _ := cmd.Flags().GetString("project")
+		phaseID, _ := cmd.Flags().GetString("phase")
+
+		exists, err := store.PhaseExists(projectID, phaseID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking phase existence: %v\n", err)
+			os.Exit(1)
+		}
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Error: Phase '%s' does not exist in project '%s'\n", phaseID, projectID)
+			os.Exit(1)
+		}
+
+		tasks, err := store.ListTasks(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		nextID, err := nextTaskID(tasks, phaseID, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if printStructured(NextTaskID{ProjectID: projectID, PhaseID: phaseID, NextID: nextID}) {
+			return
+		}
+		fmt.Println(nextID)
+	},
+}
+
+// nextTaskID returns the next free top-level task ID in a phase, e.g. T2.4
+// when T2.1-T2.3 exist. Former IDs count as taken so aliases stay unambiguous.
+func nextTaskID(tasks []Task, phaseID, suffix string) (string, error) {
+	phaseNum, err := phaseNumber(phaseID)
+	if err != nil {
+		return "", err
+	}
+
+	highest := 0
+	for _, task := range tasks {
+		for _, id := range append([]string{task.ID}, task.Aliases...) {
+			matches := taskIDPartsRegex.FindStringSubmatch(id)
+			if matches == nil || matches[1] != strconv.Itoa(phaseNum) {
+				continue
+			}
+			if n, _ := strconv.Atoi(matches[2]); n > highest {
+				highest = n
+			}
+		}
+	}
+
+	id := fmt.Sprintf("T%d.%d%s", phaseNum, highest+1, suffix)
+	if err := ValidateTaskID(id, phaseID); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// lessTaskID orders task IDs by phase and task number, so T1.2 comes before
+// T1.10 and T2.1; IDs not in the T<phase>.<task> format sort after them by name
+func lessTaskID(a, b string) bool {
+	ma, mb := taskIDPartsRegex.FindStringSubmatch(a), taskIDPartsRegex.FindStringSubmatch(b)
+	if ma == nil || mb == nil {
+		if (ma == nil) != (mb == nil) {
+			return ma != nil
+		}
+		return a < b
+	}
+	for _, i := range []int{1, 2} {
+		na, _ := strconv.Atoi(ma[i])
+		nb, _ := strconv.Atoi(mb[i])
+		if na != nb {
+			return na < nb
+		}
+	}
+	return a < b
+}
+
+// phaseNumber extracts the number of a phase ID (P2-backend -> 2)
+func phaseNumber(phaseID string) (int, error) {
+	var phaseNum int
+	if n, err := fmt.Sscanf(phaseID, "P%d", &phaseNum); n != 1 || err != nil || phaseNum < 1 {
+		return 0, fmt.Errorf("invalid phase ID format: %s", phaseID)
+	}
+	return phaseNum, nil
+}
+
+// taskIDSuffix returns the descriptive suffix of a task ID (T1.3-auth -> -auth)
+func taskIDSuffix(taskID string) string {
+	if matches := taskIDPartsRegex.FindStringSubmatch(taskID); matches != nil {
+		return matches[4]
+	}
+	return ""
+}
+
+func init() {
+	taskNextIDCmd.Flags().StringP("project", "p", "", "Project ID (required)")
+	taskNextIDCmd.Flags().String("phase", "", "Phase ID (required)")
+	taskNextIDCmd.MarkFlagRequired("project")
+	taskNextIDCmd.MarkFlagRequired("phase")
+
+	taskCmd.AddCommand(taskNextIDCmd)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestLessTaskID(t *testing.T) {
+	ids := []string{"setup", "T2.1", "T1.10", "T1.2-auth", "T1.2", "T10.1", "auth-system", "T1.2.1"}
+	sort.Slice(ids, func(i, j int) bool { return lessTaskID(ids[i], ids[j]) })
+
+	want := []string{"T1.2", "T1.2-auth", "T1.2.1", "T1.10", "T2.1", "T10.1", "auth-system", "setup"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("sorted %v, want %v", ids, want)
+		}
+	}
+}
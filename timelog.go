@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var taskLogCmd = &cobra.Command{
+	Use:   "log [task-id]",
+	Short: "Log hours worked on a task",
+	Long: `Log Time on a Task
+
+Appends an entry to the task's time_tracking.time_logs and recalculates
+time_tracking.actual_hours as the sum of all logged hours.
+
+The author is taken from DPPM_AUTHOR, the 'author' setting in
+~/.dppm/config.yaml, or the current system user. The date defaults to today.
+
+Examples:
+  dppm task log T1.1 --hours 1.5 --note "Implemented token refresh"
+  dppm task log T1.1 --hours 3 --date 2026-10-14 --note "Pairing session"
+  dppm report time --project web-app     # Compare estimates with actuals`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		hours, _ := cmd.Flags().GetFloat64("hours")
+		note, _ := cmd.Flags().GetString("note")
+		date, _ := cmd.Flags().GetString("date")
+
+		if hours <= 0 || hours > 24 {
+			fmt.Fprintln(os.Stderr, "Error: --hours must be greater than 0 and at most 24")
+			os.Exit(1)
+		}
+		if date == "" {
+			date = time.Now().Format(dateLayout)
+		} else if _, err := time.Parse(dateLayout, date); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid date '%s' (expected YYYY-MM-DD)\n", date)
+			os.Exit(1)
+		}
+		if err := ValidateDescription(note); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid note: %v\n", err)
+			os.Exit(1)
+		}
+
+		task, err := modifyTask(cmd, projectID, taskID, func(task *Task) error {
+			addTimeLog(task, TimeLog{
+				Date:        date,
+				Hours:       float32(hours),
+				Description: note,
+				Author:      currentAuthor(),
+			})
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Logged %sh on task '%s'\n", formatHours(hours), task.ID)
+		printTimeTracking(task.TimeTracking)
+	},
+}
+
+// addTimeLog appends a log entry, rolls up actual hours and records the change
+func addTimeLog(task *Task, entry TimeLog) {
+	before := formatHours(task.TimeTracking.ActualHours)
+	task.TimeTracking.TimeLogs = append(task.TimeTracking.TimeLogs, entry)
+	rollupActualHours(task)
+	recordChange(task, "time_tracking.actual_hours", before, formatHours(task.TimeTracking.ActualHours))
+}
+
+// rollupActualHours recalculates actual_hours from the time logs
+func rollupActualHours(task *Task) {
+	total := 0.0
+	for _, entry := range task.TimeTracking.TimeLogs {
+		total += float64(entry.Hours)
+	}
+	task.TimeTracking.ActualHours = roundHours(total)
+}
+
+// roundHours drops float32 noise such as 1.2000000476837158
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+func formatHours(hours float64) string {
+	return fmt.Sprintf("%g", roundHours(hours))
+}
+
+func printTimeTracking(tracking TimeTracking) {
+	if tracking.EstimatedHours > 0 {
+		fmt.Printf("⏱️  Time: %sh logged / %dh estimated\n", formatHours(tracking.ActualHours), tracking.EstimatedHours)
+	} else {
+		fmt.Printf("⏱️  Time: %sh logged\n", formatHours(tracking.ActualHours))
+	}
+}
+
+func init() {
+	taskLogCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskLogCmd.Flags().Float64("hours", 0, "Hours worked (required)")
+	taskLogCmd.Flags().StringP("note", "n", "", "What was done")
+	taskLogCmd.Flags().String("date", "", "Date worked (YYYY-MM-DD, default today)")
+	taskLogCmd.MarkFlagRequired("hours")
+
+	taskCmd.AddCommand(taskLogCmd)
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxTimerHours caps what one stopped timer logs, like 'dppm task log --hours'
+const maxTimerHours = 24
+
+// RunningTimer is the timer started with 'dppm task start'. It is kept in
+// ~/.dppm/timer.yaml, outside Dropbox, so it belongs to this machine and user.
+type RunningTimer struct {
+	ProjectID string `yaml:"project_id" json:"project_id"`
+	TaskID    string `yaml:"task_id" json:"task_id"`
+	Started   string `yaml:"started" json:"started"`
+	Author    string `yaml:"author" json:"author"`
+}
+
+var taskStartCmd = &cobra.Command{
+	Use:   "start [task-id]",
+	Short: "Start a timer on a task",
+	Long: `Start a Task Timer
+
+Starts a timer on the task. The timer is stored in ~/.dppm/timer.yaml, so it
+keeps running after dppm exits and across terminals. Only one timer can run
+at a time; starting a second one is refused unless --switch is given, which
+stops (and logs) the running timer first.
+
+Stop the timer with 'dppm task stop' to log the elapsed time.
+
+Examples:
+  dppm task start T1.1
+  dppm task start T1.2 --switch       # Log time on the running task, then start T1.2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		projectID, _ := cmd.Flags().GetString("project")
+		switchTimer, _ := cmd.Flags().GetBool("switch")
+
+		task, err := loadTaskForCommand(projectID, taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Task '%s' not found\n", taskID)
+			os.Exit(1)
+		}
+
+		unlock, err := lockTimer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		running, err := loadRunningTimer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading timer: %v\n", err)
+			os.Exit(1)
+		}
+		if running != nil {
+			if running.ProjectID == task.ProjectID && running.TaskID == task.ID {
+				fmt.Printf("⏱️  Timer already running on '%s' since %s (%s)\n", task.ID, running.Started, formatElapsed(running))
+				return
+			}
+			if !switchTimer {
+				fmt.Fprintf(os.Stderr, "⚠️  A timer is already running on '%s' (project '%s') since %s (%s)\n", running.TaskID, running.ProjectID, running.Started, formatElapsed(running))
+				fmt.Fprintln(os.Stderr, "   Stop it first with 'dppm task stop', or use --switch to stop it and start the new one")
+				os.Exit(1)
+			}
+			if err := stopRunningTimer(running, "", false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		timer := RunningTimer{
+			ProjectID: task.ProjectID,
+			TaskID:    task.ID,
+			Started:   nowTimestamp(),
+			Author:    currentAuthor(),
+		}
+		if err := saveRunningTimer(&timer); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving timer: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("⏱️  Timer started on '%s' - %s\n", task.ID, task.Title)
+		fmt.Printf("\n🎯 HØJRE HEGN - NEXT ACTIONS:\n")
+		fmt.Printf("  dppm task stop --note \"...\"  # Stop the timer and log the time\n")
+	},
+}
+
+var taskStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer and log the time",
+	Long: `Stop the Task Timer
+
+Stops the timer started with 'dppm task start' and appends the elapsed time
+to the task's time logs (see 'dppm task log'). With --in-progress a task that
+is still 'todo' is moved to 'in_progress'. --discard drops the timer without
+logging anything.
+
+A single stop logs at most 24h, the same limit as 'dppm task log'. A timer
+that was left running longer is capped with a warning; log the real time
+with 'dppm task log' if it differs.
+
+Examples:
+  dppm task stop
+  dppm task stop --note "Finished the API client" --in-progress
+  dppm task stop --discard`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		note, _ := cmd.Flags().GetString("note")
+		inProgress, _ := cmd.Flags().GetBool("in-progress")
+		discard, _ := cmd.Flags().GetBool("discard")
+
+		unlock, err := lockTimer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
+		running, err := loadRunningTimer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading timer: %v\n", err)
+			os.Exit(1)
+		}
+		if running == nil {
+			fmt.Fprintln(os.Stderr, "No timer is running. Start one with 'dppm task start [task-id]'")
+			os.Exit(1)
+		}
+
+		if discard {
+			if err := removeRunningTimer(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("🗑️  Timer on '%s' discarded (%s not logged)\n", running.TaskID, formatElapsed(running))
+			return
+		}
+
+		if err := ValidateDescription(note); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid note: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := stopRunningTimer(running, note, inProgress); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// stopRunningTimer logs the elapsed time on the timer's task and removes the
+// timer. The timer is only removed once the time log has been saved. The
+// caller holds the timer lock.
+func stopRunningTimer(running *RunningTimer, note string, inProgress bool) error {
+	started := parseTimestamp(running.Started)
+	if started.IsZero() {
+		return fmt.Errorf("timer has an invalid start time '%s'", running.Started)
+	}
+	// Log at least about a minute so very short sessions are not rounded away
+	hours := roundHours(time.Since(started).Hours())
+	if hours < 0.02 {
+		hours = 0.02
+	}
+	if hours > maxTimerHours {
+		fmt.Fprintf(os.Stderr, "⚠️  Timer on '%s' ran for %sh, probably left running; logging the %dh maximum\n", running.TaskID, formatHours(hours), maxTimerHours)
+		fmt.Fprintf(os.Stderr, "   Correct it with 'dppm task log %s --hours N' if more time was spent\n", running.TaskID)
+		hours = maxTimerHours
+	}
+
+	task, err := modifyTask(nil, running.ProjectID, running.TaskID, func(task *Task) error {
+		addTimeLog(task, TimeLog{
+			Date:        started.Local().Format(dateLayout),
+			Hours:       float32(hours),
+			Description: note,
+			Author:      running.Author,
+		})
+		if inProgress && task.Status == "todo" {
+			// The time is logged even when the workflow forbids the move
+			if err := changeTaskStatus(task, "in_progress", false); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Status not changed: %v\n", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := removeRunningTimer(); err != nil {
+		return err
+	}
+
+	fmt.Printf("⏱️  Timer stopped on '%s': logged %sh\n", task.ID, formatHours(hours))
+	printTimeTracking(task.TimeTracking)
+	if inProgress && task.Status == "in_progress" {
+		fmt.Printf("🔄 Status: %s\n", task.Status)
+	}
+	return nil
+}
+
+// lockTimer serializes access to ~/.dppm/timer.yaml between dppm processes
+func lockTimer() (func(), error) {
+	path, err := getTimerPath()
+	if err != nil {
+		return nil, err
+	}
+	return acquireFileLock(strings.TrimSuffix(path, ".yaml") + ".lock")
+}
+
+// getTimerPath returns the path of the running-timer file in ~/.dppm
+func getTimerPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	configDir := filepath.Join(home, ".dppm")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "timer.yaml"), nil
+}
+
+// loadRunningTimer returns the running timer, or nil when none is running
+func loadRunningTimer() (*RunningTimer, error) {
+	path, err := getTimerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var timer RunningTimer
+	if err := readYAMLFile(path, &timer); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if timer.TaskID == "" {
+		return nil, nil
+	}
+	return &timer, nil
+}
+
+func saveRunningTimer(timer *RunningTimer) error {
+	path, err := getTimerPath()
+	if err != nil {
+		return err
+	}
+	return writeYAMLFile(path, timer)
+}
+
+func removeRunningTimer() error {
+	path, err := getTimerPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove timer: %v", err)
+	}
+	return nil
+}
+
+func formatElapsed(timer *RunningTimer) string {
+	started := parseTimestamp(timer.Started)
+	if started.IsZero() {
+		return "unknown duration"
+	}
+	return time.Since(started).Round(time.Minute).String()
+}
+
+func init() {
+	taskStartCmd.Flags().StringP("project", "p", "", "Project ID (if not specified, searches all projects)")
+	taskStartCmd.Flags().Bool("switch", false, "Stop and log the running timer before starting this one")
+
+	taskStopCmd.Flags().StringP("note", "n", "", "What was done")
+	taskStopCmd.Flags().Bool("in-progress", false, "Move the task from todo to in_progress")
+	taskStopCmd.Flags().Bool("discard", false, "Drop the timer without logging time")
+
+	taskCmd.AddCommand(taskStartCmd)
+	taskCmd.AddCommand(taskStopCmd)
+}
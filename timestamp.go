@@ -0,0 +1,24 @@
+package main
+
+import "time"
+
+// dateLayout is the date-only format used by DPPM files written before
+// timestamps switched to RFC3339; it is still used for due and start/end dates
+const dateLayout = "2006-01-02"
+
+// nowTimestamp returns the current time in the RFC3339 format used for
+// created/updated fields
+func nowTimestamp() string {
+	return time.Now().Format(time.RFC3339)
+}
+
+// parseTimestamp reads RFC3339 timestamps as well as the date-only values of
+// older files. Unparseable values yield the zero time.
+func parseTimestamp(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, dateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -180,12 +179,5 @@ func SanitizeForYAML(s string) string {
 
 // CheckProjectExists checks if a project with the given ID already exists
 func CheckProjectExists(projectID string) (bool, error) {
-	projectPath := filepath.Join(projectsPath, "projects", projectID)
-	if _, err := os.Stat(projectPath); err == nil {
-		return true, nil
-	} else if os.IsNotExist(err) {
-		return false, nil
-	} else {
-		return false, err
-	}
-}
\ No newline at end of file
+	return store.ProjectExists(projectID)
+}
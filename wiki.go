@@ -444,18 +444,23 @@ func showDependencyGuide() {
 Dependencies ensure tasks are completed in the correct order.
 A task with dependencies cannot start until all dependency tasks are "done".
 
-📋 Creating Tasks with Dependencies:
-  # Task that depends on others:
-  dppm task create frontend-auth \
-    --project web-app \
-    --phase frontend \
-    --title "Frontend Authentication" \
-    --dependency-ids "auth-system,user-api"
+📋 Adding Dependencies:
+  # T2.1 cannot start before T1.1 and T1.3 are done:
+  dppm task dependency add T2.1 --depends-on T1.1
+  dppm task dependency add T2.1 --depends-on T1.3
+
+  # Remove a dependency again:
+  dppm task dependency remove T2.1 T1.3
+
+  # See what a task waits for (and what waits for it):
+  dppm task dependency check T2.1
+
+  Dependencies that would create a cycle are rejected.
 
 📄 Dependency Structure in YAML:
   dependency_ids: ["auth-system", "user-api"]
-  blocked_by: []      # Auto-calculated
-  blocking: []        # Auto-calculated
+  blocked_by: []      # Kept in sync by 'dppm task dependency'
+  blocking: []        # Kept in sync by 'dppm task dependency'
 
 🚫 Understanding Blocking:
   • blocked_by: Tasks that must complete before this task can start
@@ -1089,28 +1094,36 @@ Show dependency chain for project:
   dppm status dependencies --project web-app
 
 Show tasks in workable order:
-  dppm status project web-app
-  # Shows: Ready tasks → Blocked tasks
+  dppm status order --project web-app
+  # Shows: parallel waves of tasks + the critical path
+
+Weigh the critical path by estimates:
+  dppm status order --project web-app --weight hours
+  dppm status order --project web-app --weight points
 
 📊 Example Dependency Chain:
   Dependency Order for web-app:
   ============================
 
-  Level 1 (No dependencies):
-  ✅ repo-setup (done)
-  ✅ documentation (done)
+  Wave 1 (no dependencies):
+  ✅ T1.1 - Repo setup (done)
+  ✅ T1.2 - Documentation (done)
 
-  Level 2 (Depends on Level 1):
-  ✅ docker-env (done) → depends on: repo-setup
-  🔄 api-docs (in_progress) → depends on: documentation
+  Wave 2:
+  ✅ T1.3 - Docker env (done) → depends on: T1.1
+  🔄 T1.4 - API docs (in_progress) → depends on: T1.2
 
-  Level 3 (Depends on Level 2):
-  📋 database (ready) → depends on: docker-env
-  🚫 api-tests (blocked) → depends on: api-docs
+  Wave 3:
+  📋 T2.1 - Database (todo) → depends on: T1.3
+  🚫 T2.2 - API tests (todo) → depends on: T1.4
 
-  Level 4 (Depends on Level 3):
-  🚫 auth-api (blocked) → depends on: database
-  🚫 user-api (blocked) → depends on: database
+  Wave 4:
+  🚫 T2.3 - Auth API (todo) → depends on: T2.1
+  🚫 T2.4 - User API (todo) → depends on: T2.1
+
+  🎯 Critical Path (hours):
+  T2.1 → T2.3
+  Remaining length: 14 hours
 
 🎯 FINDING NEXT TASK TO WORK ON:
 
@@ -1136,10 +1149,10 @@ Bad Dependencies:
 🔧 MANAGING DEPENDENCIES:
 
 Add dependency to existing task:
-  dppm task update frontend --dependency-ids "api,auth"
+  dppm task dependency add T2.3 --depends-on T2.1
 
 Remove dependency:
-  dppm task update frontend --dependency-ids ""
+  dppm task dependency remove T2.3 T2.1
 
 ⚠️ TIPS:
   • Keep chains shallow (max 3-4 levels)
@@ -1370,52 +1383,54 @@ Find tasks by title (grep):
   dppm list tasks --project web-app | grep -i "auth"
 
 Find tasks by status:
-  dppm list tasks --project web-app --status todo
-  dppm list tasks --project web-app --status in_progress
-  dppm list tasks --project web-app --status done
+  dppm list tasks --project web-app --filter 'status=todo'
+  dppm list tasks --project web-app --filter 'status=in_progress,review'
+  dppm list tasks --project web-app --filter 'status=done'
 
-Find tasks by assignee:
-  dppm list tasks --assignee john-doe
+Find tasks by assignee (all projects):
+  dppm query 'assignee=john-doe'
 
 Find tasks by priority:
-  dppm list tasks --priority high
-  dppm list tasks --priority critical
+  dppm query 'priority=high'
+  dppm query 'priority>=high'
 
 Find tasks in phase:
-  dppm list tasks --phase backend
+  dppm list tasks --project web-app --phase P2
 
 📊 ADVANCED SEARCH PATTERNS:
 
 Find blocked tasks with specific dependency:
-  dppm status dependencies --project web-app | grep "auth-api"
+  dppm query 'depends_on:auth-api AND status!=done'
 
 Find tasks modified today:
-  dppm list tasks --updated-today
+  dppm query 'updated>=today'
 
 Find overdue tasks:
-  dppm list tasks --overdue
+  dppm query 'due_date<today AND status!=done'
 
 Find tasks with specific labels:
-  dppm list tasks --label "bug"
-  dppm list tasks --label "security"
+  dppm query 'label:bug'
+  dppm query 'label:security OR label:auth'
 
 🔧 SEARCH COMBINATIONS:
 
 High priority blocked tasks:
-  dppm status blocked --project web-app | grep "high"
+  dppm query 'status=blocked AND priority>=high'
 
 In-progress tasks by specific developer:
-  dppm list tasks --assignee john --status in_progress
+  dppm query 'assignee=john AND status=in_progress'
+
+Big open tasks in a phase:
+  dppm list tasks --project web-app --phase P2 --filter 'status!=done AND points>=8'
 
-Tasks in backend phase that are ready:
-  dppm list tasks --phase backend --status todo |
-    grep -v "blocked"
+Query output as JSON for scripts:
+  dppm query 'label:api' --output json
 
 💡 CREATE CUSTOM SEARCHES:
 
 Alias for common searches:
-  alias find-my-tasks='dppm list tasks --assignee $(whoami)'
-  alias find-urgent='dppm list tasks --priority critical'
+  alias find-my-tasks="dppm query 'assignee=$(whoami) AND status!=done'"
+  alias find-urgent="dppm query 'priority=critical'"
   alias find-blocked='dppm status blocked'
 
 Script for task search:
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Workflow is the task status state machine of a project. Transitions maps
+// each status to the statuses a task may move to from it; every status used
+// by the project must appear as a key, even when it has no way out.
+type Workflow struct {
+	Transitions map[string][]string `yaml:"transitions" json:"transitions"`
+}
+
+// defaultStatusOrder is the order statuses are listed in for the default workflow
+var defaultStatusOrder = []string{"todo", "in_progress", "review", "blocked", "done"}
+
+// defaultWorkflow carries over the rules of the old SQLite trigger and nothing
+// more: work cannot jump from todo straight to done, and done is final
+func defaultWorkflow() Workflow {
+	return Workflow{Transitions: map[string][]string{
+		"todo":        {"in_progress", "review", "blocked"},
+		"in_progress": {"todo", "review", "blocked", "done"},
+		"review":      {"todo", "in_progress", "blocked", "done"},
+		"blocked":     {"todo", "in_progress", "review", "done"},
+		"done":        {},
+	}}
+}
+
+var projectWorkflowCmd = &cobra.Command{
+	Use:   "workflow [project-id]",
+	Short: "Show the task status workflow of a project",
+	Long: `Show Task Status Workflow
+
+Task status changes are checked against the project's workflow. Without a
+'workflow' section in project.yaml the default applies:
+
+  todo         → in_progress, review, blocked
+  in_progress  → todo, review, blocked, done
+  review       → todo, in_progress, blocked, done
+  blocked      → todo, in_progress, review, done
+  done         → (final)
+
+These are the rules the SQLite database enforced: a task cannot go from
+todo straight to done, and done is final.
+
+To customise it, add a workflow section to project.yaml listing every status
+and where it may go:
+
+  workflow:
+    transitions:
+      todo: [in_progress, done]
+      in_progress: [todo, done]
+      done: [in_progress]
+
+'dppm task update --force' bypasses the workflow; forced changes are marked
+in the task history.
+
+Examples:
+  dppm project workflow web-app
+  dppm project workflow web-app --output yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		workflow, err := projectWorkflow(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printStructured(workflow) {
+			return
+		}
+
+		fmt.Printf("Task Workflow: %s\n", args[0])
+		fmt.Println("==================")
+		for _, status := range workflow.statuses() {
+			targets := workflow.Transitions[status]
+			if len(targets) == 0 {
+				fmt.Printf("  %-12s → (final)\n", status)
+				continue
+			}
+			fmt.Printf("  %-12s → %s\n", status, strings.Join(targets, ", "))
+		}
+	},
+}
+
+// projectWorkflow returns the project's workflow, or the default one
+func projectWorkflow(projectID string) (Workflow, error) {
+	project, err := store.LoadProject(projectID)
+	if err != nil {
+		return Workflow{}, err
+	}
+	if project.Workflow == nil || len(project.Workflow.Transitions) == 0 {
+		return defaultWorkflow(), nil
+	}
+	return *project.Workflow, nil
+}
+
+// statuses lists the statuses of the workflow, default ones first
+func (w Workflow) statuses() []string {
+	var statuses, custom []string
+	for _, status := range defaultStatusOrder {
+		if _, exists := w.Transitions[status]; exists {
+			statuses = append(statuses, status)
+		}
+	}
+	for status := range w.Transitions {
+		if !containsString(defaultStatusOrder, status) {
+			custom = append(custom, status)
+		}
+	}
+	sort.Strings(custom)
+	return append(statuses, custom...)
+}
+
+// allows reports whether a task may move from one status to another. Tasks in a
+// status the workflow does not know (e.g. from older files) may move anywhere.
+func (w Workflow) allows(from, to string) bool {
+	if from == to {
+		return true
+	}
+	targets, known := w.Transitions[from]
+	if !known {
+		return true
+	}
+	return containsString(targets, to)
+}
+
+// changeTaskStatus moves a task to a new status after checking the project's
+// workflow. force bypasses the transition rules, but not unknown statuses, and
+// is recorded in the task history.
+func changeTaskStatus(task *Task, status string, force bool) error {
+	workflow, err := projectWorkflow(task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	if _, known := workflow.Transitions[status]; !known {
+		return fmt.Errorf("invalid status '%s'. Must be one of: %s", status, strings.Join(workflow.statuses(), ", "))
+	}
+
+	if !workflow.allows(task.Status, status) {
+		if !force {
+			allowed := strings.Join(workflow.Transitions[task.Status], ", ")
+			if allowed == "" {
+				allowed = "none, it is final"
+			}
+			return fmt.Errorf("task '%s' cannot move from '%s' to '%s' (allowed: %s). Use --force to override", task.ID, task.Status, status, allowed)
+		}
+		recordChange(task, "status", task.Status, status)
+		task.History[len(task.History)-1].Forced = true
+		task.Status = status
+		return nil
+	}
+
+	recordChange(task, "status", task.Status, status)
+	task.Status = status
+	return nil
+}
+
+func init() {
+	projectCmd.AddCommand(projectWorkflowCmd)
+}
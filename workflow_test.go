@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangeTaskStatusDefaultWorkflow(t *testing.T) {
+	store = NewMemoryStore()
+	if err := store.SaveProject(&Project{ID: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{"todo", "in_progress", true},
+		{"todo", "blocked", true},
+		{"todo", "done", false},
+		{"todo", "review", true},
+		{"in_progress", "done", true},
+		{"in_progress", "todo", true},
+		{"review", "done", true},
+		{"review", "todo", true},
+		{"blocked", "in_progress", true},
+		{"blocked", "done", true},
+		{"done", "todo", false},
+		{"done", "in_progress", false},
+		{"done", "done", true},
+		{"", "done", true},
+	}
+	for _, test := range tests {
+		task := &Task{ID: "T1.1", ProjectID: "demo", Status: test.from}
+		err := changeTaskStatus(task, test.to, false)
+		if (err == nil) != test.allowed {
+			t.Errorf("%q → %q: error %v, want allowed %v", test.from, test.to, err, test.allowed)
+			continue
+		}
+		if err == nil && task.Status != test.to {
+			t.Errorf("%q → %q: status is %q", test.from, test.to, task.Status)
+		}
+		if err != nil && task.Status != test.from {
+			t.Errorf("%q → %q refused but status changed to %q", test.from, test.to, task.Status)
+		}
+	}
+}
+
+func TestChangeTaskStatusForce(t *testing.T) {
+	store = NewMemoryStore()
+	if err := store.SaveProject(&Project{ID: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &Task{ID: "T1.1", ProjectID: "demo", Status: "done"}
+	if err := changeTaskStatus(task, "todo", false); err == nil || !strings.Contains(err.Error(), "none, it is final") {
+		t.Fatalf("leaving done without --force: %v", err)
+	}
+	if err := changeTaskStatus(task, "todo", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := changeTaskStatus(task, "in_progress", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if task.Status != "in_progress" || len(task.History) != 2 {
+		t.Fatalf("status %q with %d history entries", task.Status, len(task.History))
+	}
+	forced, allowed := task.History[0], task.History[1]
+	if forced.Field != "status" || forced.From != "done" || forced.To != "todo" || !forced.Forced {
+		t.Errorf("forced change recorded as %+v", forced)
+	}
+	if allowed.Forced {
+		t.Errorf("allowed change marked as forced: %+v", allowed)
+	}
+
+	if err := changeTaskStatus(task, "finished", true); err == nil || !strings.Contains(err.Error(), "invalid status 'finished'") {
+		t.Errorf("--force with an unknown status: %v", err)
+	}
+}
+
+func TestChangeTaskStatusProjectWorkflow(t *testing.T) {
+	store = NewMemoryStore()
+	workflow := &Workflow{Transitions: map[string][]string{
+		"todo":  {"done"},
+		"done":  {"todo"},
+		"later": {},
+	}}
+	if err := store.SaveProject(&Project{ID: "demo", Workflow: workflow}); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &Task{ID: "T1.1", ProjectID: "demo", Status: "todo"}
+	if err := changeTaskStatus(task, "done", false); err != nil {
+		t.Errorf("todo → done: %v", err)
+	}
+	if err := changeTaskStatus(task, "later", false); err == nil {
+		t.Error("done → later allowed, the workflow only lists todo")
+	}
+	if err := changeTaskStatus(task, "in_progress", false); err == nil || !strings.Contains(err.Error(), "Must be one of: todo, done, later") {
+		t.Errorf("status missing from the workflow: %v", err)
+	}
+}
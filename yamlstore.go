@@ -0,0 +1,430 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLStore is the Dropbox folder backend. It keeps the original layout:
+//
+//	ROOT/projects/PROJECT_ID/project.yaml
+//	ROOT/projects/PROJECT_ID/phases/PHASE_ID/phase.yaml
+//	ROOT/projects/PROJECT_ID/phases/PHASE_ID/tasks/TASK_ID.yaml
+//	ROOT/projects/PROJECT_ID/tasks/TASK_ID.yaml  (tasks without a phase)
+type YAMLStore struct {
+	root string
+}
+
+// NewYAMLStore creates a store rooted at the project-management folder
+func NewYAMLStore(root string) *YAMLStore {
+	return &YAMLStore{root: root}
+}
+
+func (s *YAMLStore) projectsDir() string {
+	return filepath.Join(s.root, "projects")
+}
+
+func (s *YAMLStore) projectDir(projectID string) string {
+	return filepath.Join(s.projectsDir(), projectID)
+}
+
+func (s *YAMLStore) phaseDir(projectID, phaseID string) string {
+	return filepath.Join(s.projectDir(projectID), "phases", phaseID)
+}
+
+func (s *YAMLStore) taskDir(projectID, phaseID string) string {
+	if phaseID != "" {
+		return filepath.Join(s.phaseDir(projectID, phaseID), "tasks")
+	}
+	return filepath.Join(s.projectDir(projectID), "tasks")
+}
+
+// ListProjects returns every project with a readable project.yaml
+func (s *YAMLStore) ListProjects() ([]Project, error) {
+	entries, err := os.ReadDir(s.projectsDir())
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		project, err := s.LoadProject(entry.Name())
+		if err != nil {
+			continue
+		}
+		projects = append(projects, *project)
+	}
+
+	return projects, nil
+}
+
+// ProjectExists checks whether the project directory exists
+func (s *YAMLStore) ProjectExists(projectID string) (bool, error) {
+	return dirExists(s.projectDir(projectID))
+}
+
+// LoadProject reads project.yaml for the given project
+func (s *YAMLStore) LoadProject(projectID string) (*Project, error) {
+	var project Project
+	if err := readYAMLFile(filepath.Join(s.projectDir(projectID), "project.yaml"), &project); err != nil {
+		if os.IsNotExist(err) {
+			return nil, notFoundError("project", projectID)
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// SaveProject writes project.yaml, creating the project and phases directories
+func (s *YAMLStore) SaveProject(project *Project) error {
+	if existing, err := s.LoadProject(project.ID); err == nil && existing.Revision != project.Revision {
+		return revisionConflictError("project", project.ID, existing.Revision, project.Revision)
+	}
+
+	projectDir := s.projectDir(project.ID)
+	if err := os.MkdirAll(filepath.Join(projectDir, "phases"), 0755); err != nil {
+		return fmt.Errorf("failed to create project directory: %v", err)
+	}
+
+	next := *project
+	next.Revision++
+	if err := writeYAMLFile(filepath.Join(projectDir, "project.yaml"), &next); err != nil {
+		return err
+	}
+	project.Revision = next.Revision
+	return nil
+}
+
+// ListPhases returns every phase with a readable phase.yaml
+func (s *YAMLStore) ListPhases(projectID string) ([]Phase, error) {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir(projectID), "phases"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var phases []Phase
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		phase, err := s.LoadPhase(projectID, entry.Name())
+		if err != nil {
+			continue
+		}
+		phases = append(phases, *phase)
+	}
+
+	return phases, nil
+}
+
+// PhaseExists checks whether the phase directory exists
+func (s *YAMLStore) PhaseExists(projectID, phaseID string) (bool, error) {
+	return dirExists(s.phaseDir(projectID, phaseID))
+}
+
+// LoadPhase reads phase.yaml for the given phase
+func (s *YAMLStore) LoadPhase(projectID, phaseID string) (*Phase, error) {
+	var phase Phase
+	if err := readYAMLFile(filepath.Join(s.phaseDir(projectID, phaseID), "phase.yaml"), &phase); err != nil {
+		if os.IsNotExist(err) {
+			return nil, notFoundError("phase", phaseID)
+		}
+		return nil, err
+	}
+	return &phase, nil
+}
+
+// SavePhase writes phase.yaml, creating the phase and tasks directories
+func (s *YAMLStore) SavePhase(phase *Phase) error {
+	if existing, err := s.LoadPhase(phase.ProjectID, phase.ID); err == nil && existing.Revision != phase.Revision {
+		return revisionConflictError("phase", phase.ID, existing.Revision, phase.Revision)
+	}
+
+	phaseDir := s.phaseDir(phase.ProjectID, phase.ID)
+	if err := os.MkdirAll(filepath.Join(phaseDir, "tasks"), 0755); err != nil {
+		return fmt.Errorf("failed to create phase directory: %v", err)
+	}
+
+	next := *phase
+	next.Revision++
+	if err := writeYAMLFile(filepath.Join(phaseDir, "phase.yaml"), &next); err != nil {
+		return err
+	}
+	phase.Revision = next.Revision
+	return nil
+}
+
+// ListTasks loads all tasks in a project, from phase folders first and then
+// from the project-level tasks folder kept for backwards compatibility
+func (s *YAMLStore) ListTasks(projectID string) ([]Task, error) {
+	var tasks []Task
+
+	phasesDir := filepath.Join(s.projectDir(projectID), "phases")
+	phaseEntries, err := os.ReadDir(phasesDir)
+	if err == nil {
+		for _, phaseEntry := range phaseEntries {
+			if phaseEntry.IsDir() {
+				tasksDir := filepath.Join(phasesDir, phaseEntry.Name(), "tasks")
+				if phaseTasks, err := loadTasksFromDir(tasksDir); err == nil {
+					tasks = append(tasks, phaseTasks...)
+				}
+			}
+		}
+	}
+
+	if directTasks, err := loadTasksFromDir(s.taskDir(projectID, "")); err == nil {
+		tasks = append(tasks, directTasks...)
+	}
+
+	return tasks, nil
+}
+
+// LoadTask finds a task in the project-level tasks folder or any phase folder,
+// falling back to a task that lists taskID among its aliases
+func (s *YAMLStore) LoadTask(projectID, taskID string) (*Task, error) {
+	task, err := s.loadTaskFile(projectID, taskID)
+	if !isNotFound(err) {
+		return task, err
+	}
+
+	tasks, listErr := s.ListTasks(projectID)
+	if listErr != nil {
+		return nil, listErr
+	}
+	if aliased := findTaskByAlias(tasks, taskID); aliased != nil {
+		return aliased, nil
+	}
+	return nil, err
+}
+
+// loadTaskFile reads the file of a task by its current ID
+func (s *YAMLStore) loadTaskFile(projectID, taskID string) (*Task, error) {
+	taskFile, err := s.taskFile(projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := readYAMLFile(taskFile, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SaveTask writes an existing task back to the file it was loaded from, so
+// legacy tasks stay in the project-level tasks folder, and a new task into the
+// folder of its phase
+func (s *YAMLStore) SaveTask(task *Task) error {
+	taskFile, err := s.taskFile(task.ProjectID, task.ID)
+	switch {
+	case err == nil:
+		var existing Task
+		if err := readYAMLFile(taskFile, &existing); err == nil && existing.Revision != task.Revision {
+			return revisionConflictError("task", task.ID, existing.Revision, task.Revision)
+		}
+	case isNotFound(err):
+		taskDir := s.taskDir(task.ProjectID, task.PhaseID)
+		if err := os.MkdirAll(taskDir, 0755); err != nil {
+			return fmt.Errorf("failed to create task directory: %v", err)
+		}
+		taskFile = filepath.Join(taskDir, task.ID+".yaml")
+	default:
+		return err
+	}
+
+	next := *task
+	next.Revision++
+	if err := writeYAMLFile(taskFile, &next); err != nil {
+		return err
+	}
+	task.Revision = next.Revision
+	return nil
+}
+
+// DeleteTask removes the task file wherever it lives in the project
+func (s *YAMLStore) DeleteTask(projectID, taskID string) error {
+	taskFile, err := s.taskFile(projectID, taskID)
+	if err != nil {
+		return err
+	}
+	return os.Remove(taskFile)
+}
+
+// MoveTask writes the task into the folder of its (new) phase under its (new)
+// ID and then removes the old file
+func (s *YAMLStore) MoveTask(oldID string, task *Task) error {
+	oldFile, err := s.taskFile(task.ProjectID, oldID)
+	if err != nil {
+		return err
+	}
+	var existing Task
+	if err := readYAMLFile(oldFile, &existing); err != nil {
+		return err
+	}
+	if existing.Revision != task.Revision {
+		return revisionConflictError("task", oldID, existing.Revision, task.Revision)
+	}
+	if oldID != task.ID {
+		if _, err := s.taskFile(task.ProjectID, task.ID); err == nil {
+			return fmt.Errorf("task '%s' already exists in project '%s'", task.ID, task.ProjectID)
+		}
+	}
+
+	taskDir := s.taskDir(task.ProjectID, task.PhaseID)
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		return fmt.Errorf("failed to create task directory: %v", err)
+	}
+
+	newFile := filepath.Join(taskDir, task.ID+".yaml")
+	next := *task
+	next.Revision++
+	if err := writeYAMLFile(newFile, &next); err != nil {
+		return err
+	}
+	if newFile != oldFile {
+		if err := os.Remove(oldFile); err != nil {
+			return fmt.Errorf("task saved to %s but the old file could not be removed: %v", newFile, err)
+		}
+	}
+	task.Revision = next.Revision
+	return nil
+}
+
+// Lock takes the per-project advisory lock file shared by all dppm processes
+func (s *YAMLStore) Lock(projectID string) (func(), error) {
+	lockDir, err := getLockDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare lock directory: %v", err)
+	}
+	return acquireFileLock(filepath.Join(lockDir, projectID+".lock"))
+}
+
+// taskFile resolves the on-disk path of a task
+func (s *YAMLStore) taskFile(projectID, taskID string) (string, error) {
+	taskFile := filepath.Join(s.taskDir(projectID, ""), taskID+".yaml")
+	if _, err := os.Stat(taskFile); err == nil {
+		return taskFile, nil
+	}
+
+	phasesDir := filepath.Join(s.projectDir(projectID), "phases")
+	phaseEntries, err := os.ReadDir(phasesDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, phaseEntry := range phaseEntries {
+		if !phaseEntry.IsDir() {
+			continue
+		}
+		taskFile = filepath.Join(s.taskDir(projectID, phaseEntry.Name()), taskID+".yaml")
+		if _, err := os.Stat(taskFile); err == nil {
+			return taskFile, nil
+		}
+	}
+
+	return "", notFoundError("task", taskID)
+}
+
+func loadTasksFromDir(tasksDir string) ([]Task, error) {
+	var tasks []Task
+
+	entries, err := os.ReadDir(tasksDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
+			// Dropbox conflicted copies are resolved with 'dppm sync conflicts',
+			// never loaded as duplicate tasks
+			if isConflictedCopy(entry.Name()) {
+				continue
+			}
+
+			var task Task
+			if err := readYAMLFile(filepath.Join(tasksDir, entry.Name()), &task); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Skipping unreadable task file %s: %v\n", entry.Name(), err)
+				continue
+			}
+
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
+
+func readYAMLFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// writeYAMLFile atomically replaces path with the YAML encoding of v
+func writeYAMLFile(path string, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", filepath.Base(path), err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic atomically replaces path: the data goes to a temp file in the
+// same directory which is synced and then renamed over the original, so a crash
+// never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write %s: %v", filepath.Base(path), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to sync %s: %v", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close %s: %v", filepath.Base(path), err)
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func dirExists(path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return true, nil
+	} else if os.IsNotExist(err) {
+		return false, nil
+	} else {
+		return false, err
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveTaskKeepsLegacyLocation(t *testing.T) {
+	root := t.TempDir()
+	s := NewYAMLStore(root)
+	if err := s.SavePhase(&Phase{ID: "P1", ProjectID: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tasks from before phase folders live in the project-level tasks folder
+	legacyFile := filepath.Join(root, "projects", "demo", "tasks", "T1.1.yaml")
+	if err := os.MkdirAll(filepath.Dir(legacyFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeYAMLFile(legacyFile, &Task{ID: "T1.1", ProjectID: "demo", PhaseID: "P1", Title: "Old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := s.LoadTask("demo", "T1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Title = "Updated"
+	if err := s.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	var saved Task
+	if err := readYAMLFile(legacyFile, &saved); err != nil || saved.Title != "Updated" {
+		t.Errorf("legacy file holds %q, %v", saved.Title, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "projects", "demo", "phases", "P1", "tasks", "T1.1.yaml")); !os.IsNotExist(err) {
+		t.Errorf("save created a second copy in the phase folder: %v", err)
+	}
+	if tasks, _ := s.ListTasks("demo"); len(tasks) != 1 {
+		t.Errorf("ListTasks returned %d tasks, want 1", len(tasks))
+	}
+
+	// New tasks still go into the folder of their phase
+	if err := s.SaveTask(&Task{ID: "T1.2", ProjectID: "demo", PhaseID: "P1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "projects", "demo", "phases", "P1", "tasks", "T1.2.yaml")); err != nil {
+		t.Errorf("new task not in its phase folder: %v", err)
+	}
+}